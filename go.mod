@@ -3,15 +3,21 @@ module github.com/mannion007/payments-prototype
 go 1.13
 
 require (
+	github.com/Shopify/sarama v1.26.0
 	github.com/ThreeDotsLabs/watermill v1.1.1
 	github.com/ThreeDotsLabs/watermill-amqp v1.1.0
 	github.com/ThreeDotsLabs/watermill-http v1.1.3
+	github.com/ThreeDotsLabs/watermill-kafka/v2 v2.2.1
+	github.com/ThreeDotsLabs/watermill-sql v1.3.8
+	github.com/go-chi/chi v4.0.2+incompatible
 	github.com/gogo/protobuf v1.2.1
 	github.com/golang/protobuf v1.4.3
 	github.com/google/uuid v1.2.0
 	github.com/lithammer/shortuuid/v3 v3.0.5 // indirect
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/streadway/amqp v1.0.0 // indirect
 	github.com/stripe/stripe-go v70.15.0+incompatible
-	github.com/stripe/stripe-go/v72 v72.33.0 // indirect
+	github.com/stripe/stripe-go/v72 v72.33.0
+	go.etcd.io/bbolt v1.3.5
 	google.golang.org/protobuf v1.25.0
 )
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mannion007/payments-prototype/pkg/api"
+	"github.com/mannion007/payments-prototype/pkg/config"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// TestPay takes payments through the whole pipeline, from the pay endpoint to their outcome, with the simulator
+// processing them and the commands and events carried over go channels
+func TestPay(t *testing.T) {
+
+	cfg := config.Default()
+	cfg.Transport.Driver = config.TransportGoChannel
+	cfg.Processor.Name = "simulator"
+	cfg.Processor.Timeout = config.Duration{Duration: 5 * time.Second}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- run(ctx, cfg, listener)
+	}()
+	defer func() {
+		cancel()
+		if err := <-stopped; err != nil {
+			t.Errorf("failed to run, %s", err)
+		}
+	}()
+
+	baseURL := "http://" + listener.Addr().String()
+
+	tests := []struct {
+		name        string
+		card        string
+		status      string
+		declineCode string
+	}{
+		{name: "captured", card: "4242424242424242", status: payment.StatusCaptured},
+		{name: "declined", card: "4000000000000002", status: payment.StatusFailed, declineCode: "generic_decline"},
+		{name: "insufficient funds", card: "4000000000009995", status: payment.StatusFailed, declineCode: "insufficient_funds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			id := uuid.New().String()
+			body := fmt.Sprintf(`{
+				"idempotency_token": %q,
+				"payee_id": "fbc8fa45-9041-42ea-abe0-2dc9c7581123",
+				"amount": {"currency": "GBP", "value": 9999},
+				"card": {"number": %q, "expiry": {"year": "2030", "month": "10"}}
+			}`, id, tt.card)
+
+			resp, err := http.Post(baseURL+"/pay", "application/json", bytes.NewBufferString(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected the outcome to be responded with, got %d", resp.StatusCode)
+			}
+
+			outcome := &api.PaymentResponse{}
+			if err := json.NewDecoder(resp.Body).Decode(outcome); err != nil {
+				t.Fatal(err)
+			}
+			if outcome.ID != id || outcome.Status != tt.status || outcome.DeclineCode != tt.declineCode || outcome.Processor != "simulator" {
+				t.Fatalf("expected payment %s to be %s by the simulator with decline code %q, got %+v", id, tt.status, tt.declineCode, outcome)
+			}
+
+			// the outcome is recorded once the event with it has been handled
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				recorded := getPayment(t, baseURL, id)
+				if recorded.Status == tt.status {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("expected payment %s to be recorded as %s, it is %s", id, tt.status, recorded.Status)
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+		})
+	}
+}
+
+// getPayment gets the status of a payment from the web api
+func getPayment(t *testing.T, baseURL, id string) *api.PaymentResponse {
+
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/payments/" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	p := &api.PaymentResponse{}
+	if resp.StatusCode != http.StatusOK {
+		return p
+	}
+	if err := json.NewDecoder(resp.Body).Decode(p); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
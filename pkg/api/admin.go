@@ -0,0 +1,22 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// RequireToken is middleware for the admin endpoints which rejects requests without the token as their bearer token with a 401
+func RequireToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJSON(w, http.StatusUnauthorized, &ErrorResponse{Error: "a valid admin token is required"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
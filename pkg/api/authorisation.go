@@ -0,0 +1,76 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mannion007/payments-prototype/pkg/handler"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// ValidateCaptureRequest is middleware for the capture endpoint which rejects captures which cannot be made before they reach the bus.
+// Captures of unknown payments are rejected with a 404, of payments which are not authorised with a 409,
+// and requests with invalid fields, or for more than was authorised, with a 422
+func ValidateCaptureRequest(payments store.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			cr := &handler.CaptureRequest{}
+			if !decodeCommand(w, r, cr) {
+				return
+			}
+
+			p := findPayment(w, r, payments)
+			if p == nil {
+				return
+			}
+
+			if err := p.Allows(payment.TypeCapture); err != nil {
+				writeJSON(w, http.StatusConflict, &ErrorResponse{Error: err.Error()})
+				return
+			}
+
+			capturable := p.CapturableAmount()
+
+			if cr.Amount > capturable {
+				ve := &handler.ValidationError{Errors: []handler.FieldError{{
+					Field:   "amount",
+					Code:    handler.CodeOutOfRange,
+					Message: fmt.Sprintf("must be no more than the %d authorised", capturable),
+				}}}
+				writeJSON(w, http.StatusUnprocessableEntity, ve)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ValidateVoidRequest is middleware for the void endpoint which rejects voids which cannot be made before they reach the bus.
+// Voids of unknown payments are rejected with a 404, of payments which are not authorised with a 409,
+// and requests with invalid fields with a 422
+func ValidateVoidRequest(payments store.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			vr := &handler.VoidRequest{}
+			if !decodeCommand(w, r, vr) {
+				return
+			}
+
+			p := findPayment(w, r, payments)
+			if p == nil {
+				return
+			}
+
+			if err := p.Allows(payment.TypeVoid); err != nil {
+				writeJSON(w, http.StatusConflict, &ErrorResponse{Error: err.Error()})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
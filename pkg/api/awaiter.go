@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/handler"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// OutcomeAwaiter hands Outcomes consumed from the bus to the http requests waiting on them
+type OutcomeAwaiter struct {
+	lock    sync.Mutex
+	waiting map[string][]chan *payment.Outcome
+}
+
+// Register starts waiting for the Outcome of a Claim, returning a channel which receives it and a func to stop waiting
+func (oa *OutcomeAwaiter) Register(claimID string) (<-chan *payment.Outcome, func()) {
+
+	ch := make(chan *payment.Outcome, 1)
+
+	oa.lock.Lock()
+	oa.waiting[claimID] = append(oa.waiting[claimID], ch)
+	oa.lock.Unlock()
+
+	cancel := func() {
+		oa.lock.Lock()
+		defer oa.lock.Unlock()
+
+		waiting := oa.waiting[claimID]
+		for i, w := range waiting {
+			if w == ch {
+				waiting = append(waiting[:i], waiting[i+1:]...)
+				break
+			}
+		}
+
+		if len(waiting) == 0 {
+			delete(oa.waiting, claimID)
+			return
+		}
+		oa.waiting[claimID] = waiting
+	}
+
+	return ch, cancel
+}
+
+// Handle passes an Outcome message to everything waiting on it, returning an error, if any
+func (oa *OutcomeAwaiter) Handle(msg *message.Message) error {
+
+	if handler.MessageType(msg, payment.TypeOutcome) != payment.TypeOutcome {
+		return nil
+	}
+
+	outcome := &payment.Outcome{}
+
+	err := proto.Unmarshal(msg.Payload, outcome)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal outcome, %s", err.Error())
+	}
+
+	oa.lock.Lock()
+	defer oa.lock.Unlock()
+
+	for _, ch := range oa.waiting[outcome.ClaimId] {
+		select {
+		case ch <- outcome:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// NewOutcomeAwaiter is a factory for an OutcomeAwaiter with nothing waiting
+func NewOutcomeAwaiter() *OutcomeAwaiter {
+	return &OutcomeAwaiter{
+		waiting: make(map[string][]chan *payment.Outcome),
+	}
+}
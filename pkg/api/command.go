@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// CommandResponse is the representation of a command acting on a payment, such as a refund, returned to http callers
+type CommandResponse struct {
+	ID        string `json:"id"`
+	PaymentID string `json:"payment_id"`
+	Status    string `json:"status"`
+	Amount    int64  `json:"amount,omitempty"`
+	StatusURL string `json:"status_url"`
+}
+
+// commandRequest is a request to act on a payment
+type commandRequest interface {
+	Validate() error
+}
+
+// decodeCommand reads a request to act on a payment from the body, writing an error response and returning false if it is invalid
+func decodeCommand(w http.ResponseWriter, r *http.Request, cr commandRequest) bool {
+
+	b, err := readBody(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: "failed to read request body"})
+		return false
+	}
+
+	if err := json.Unmarshal(b, cr); err != nil {
+		writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: "request body must be a json request"})
+		return false
+	}
+
+	if err := cr.Validate(); err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, err)
+		return false
+	}
+
+	return true
+}
+
+// findPayment gets the payment named in the url, writing an error response and returning nil if it cannot
+func findPayment(w http.ResponseWriter, r *http.Request, payments store.Store) *store.Payment {
+
+	p, err := payments.Get(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{Error: err.Error()})
+		return nil
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return nil
+	}
+
+	return p
+}
+
+// AcceptCommand is middleware for the endpoints acting on a payment which responds with a 202 once the command has been accepted onto the bus
+func AcceptCommand(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		b, err := readBody(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		cr := &struct {
+			IdempotencyToken string `json:"idempotency_token"`
+			Amount           int64  `json:"amount"`
+		}{}
+		if err := json.Unmarshal(b, cr); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &statusRecorder{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status != http.StatusOK {
+			w.WriteHeader(rec.status)
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+
+		w.Header().Set("Location", statusURL(id))
+		writeJSON(w, http.StatusAccepted, &CommandResponse{
+			ID:        cr.IdempotencyToken,
+			PaymentID: id,
+			Status:    payment.StatusPending,
+			Amount:    cr.Amount,
+			StatusURL: statusURL(id),
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/mannion007/payments-prototype/pkg/handler"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// ValidateConfirmRequest is middleware for the confirm endpoint which rejects confirmations which cannot be made before they reach the bus.
+// Confirmations of unknown payments are rejected with a 404, of payments which do not require action with a 409,
+// and requests with invalid fields with a 422
+func ValidateConfirmRequest(payments store.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			cr := &handler.ConfirmRequest{}
+			if !decodeCommand(w, r, cr) {
+				return
+			}
+
+			p := findPayment(w, r, payments)
+			if p == nil {
+				return
+			}
+
+			if err := p.Allows(payment.TypeConfirm); err != nil {
+				writeJSON(w, http.StatusConflict, &ErrorResponse{Error: err.Error()})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
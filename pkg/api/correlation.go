@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ThreeDotsLabs/watermill"
+)
+
+// CorrelationIDHeader is the http header carrying the id which correlates everything done for a request
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// CorrelationID is middleware which makes sure every request has a correlation id, generating one if the caller did not send it.
+// The id is echoed in the response and logged along with the outcome of the request
+func CorrelationID(logger watermill.LoggerAdapter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			id := r.Header.Get(CorrelationIDHeader)
+			if id == "" {
+				id = watermill.NewUUID()
+				r.Header.Set(CorrelationIDHeader, id)
+			}
+			w.Header().Set(CorrelationIDHeader, id)
+
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			logger.Info("Handled http request", watermill.LogFields{
+				"method":         r.Method,
+				"path":           r.URL.Path,
+				"status":         rec.status,
+				"correlation_id": id,
+			})
+		})
+	}
+}
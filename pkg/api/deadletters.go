@@ -0,0 +1,174 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/go-chi/chi"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/deadletter"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// DeadLetterResponse is the representation of a dead letter returned to http callers. The command it carried is only
+// included when a single dead letter is inspected, with the number of any card in it masked
+type DeadLetterResponse struct {
+	ID             string            `json:"id"`
+	Type           string            `json:"type"`
+	PaymentID      string            `json:"payment_id,omitempty"`
+	Topic          string            `json:"topic"`
+	Handler        string            `json:"handler"`
+	Reason         string            `json:"reason"`
+	Attempts       int               `json:"attempts"`
+	DeadLetteredAt time.Time         `json:"dead_lettered_at"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Command        proto.Message     `json:"command,omitempty"`
+	CommandError   string            `json:"command_error,omitempty"`
+}
+
+// DeadLetterListResponse is every dead letter returned to http callers
+type DeadLetterListResponse struct {
+	DeadLetters []*DeadLetterResponse `json:"dead_letters"`
+}
+
+// DeadLetters serves the commands which could not be handled from a Store, and publishes them again when replayed
+type DeadLetters struct {
+	Store     deadletter.Store
+	Publisher message.Publisher
+}
+
+// List responds with every dead letter, the longest dead lettered first
+func (dl DeadLetters) List(w http.ResponseWriter, r *http.Request) {
+
+	letters, err := dl.Store.List()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp := &DeadLetterListResponse{DeadLetters: make([]*DeadLetterResponse, 0, len(letters))}
+	for _, l := range letters {
+		letter, _ := deadLetterResponse(l)
+		resp.DeadLetters = append(resp.DeadLetters, letter)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Get responds with the dead letter identified by the id in the url, along with the command it carried
+func (dl DeadLetters) Get(w http.ResponseWriter, r *http.Request) {
+
+	l := dl.find(w, r)
+	if l == nil {
+		return
+	}
+
+	resp, command := deadLetterResponse(l)
+	if command != nil {
+		payment.MaskCard(command)
+		resp.Command = command
+	}
+	resp.Metadata = l.Metadata
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Replay publishes the dead letter identified by the id in the url to its topic again, as it was before it failed,
+// then forgets it, responding with a 202 Accepted once it is published. The number of the card a payment would be taken
+// from is masked before a command is dead lettered, so those commands cannot be replayed, the payment has to be made again
+func (dl DeadLetters) Replay(w http.ResponseWriter, r *http.Request) {
+
+	l := dl.find(w, r)
+	if l == nil {
+		return
+	}
+
+	if l.Topic == "" {
+		writeJSON(w, http.StatusConflict, &ErrorResponse{Error: "the topic the dead letter was taken from is not known"})
+		return
+	}
+
+	if _, command := deadLetterResponse(l); payment.IsMaskedCardNumber(payment.ClaimOf(command).GetPayer().GetNumber()) {
+		writeJSON(w, http.StatusConflict, &ErrorResponse{Error: "the number of the card the dead letter would take a payment from is masked, so it cannot be replayed"})
+		return
+	}
+
+	if err := dl.Publisher.Publish(l.Topic, l.Message()); err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: "failed to replay dead letter, " + err.Error()})
+		return
+	}
+
+	if err := dl.Store.Delete(l.ID); err != nil && err != deadletter.ErrNotFound {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp, _ := deadLetterResponse(l)
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// Discard forgets the dead letter identified by the id in the url without it being handled, responding with a 204 No Content
+func (dl DeadLetters) Discard(w http.ResponseWriter, r *http.Request) {
+
+	err := dl.Store.Delete(chi.URLParam(r, "id"))
+	if err == deadletter.ErrNotFound {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// find gets the dead letter named in the url, writing an error response and returning nil if it cannot
+func (dl DeadLetters) find(w http.ResponseWriter, r *http.Request) *deadletter.Letter {
+
+	l, err := dl.Store.Get(chi.URLParam(r, "id"))
+	if err == deadletter.ErrNotFound {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{Error: err.Error()})
+		return nil
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return nil
+	}
+
+	return l
+}
+
+// NewDeadLetters is a factory for DeadLetters
+func NewDeadLetters(s deadletter.Store, publisher message.Publisher) *DeadLetters {
+	return &DeadLetters{Store: s, Publisher: publisher}
+}
+
+// deadLetterResponse describes a dead letter, along with the command it carried, which is nil when it cannot be decoded
+func deadLetterResponse(l *deadletter.Letter) (*DeadLetterResponse, proto.Message) {
+
+	resp := &DeadLetterResponse{
+		ID:             l.ID,
+		Type:           l.Metadata[payment.MessageTypeKey],
+		Topic:          l.Topic,
+		Handler:        l.Handler,
+		Reason:         l.Reason,
+		Attempts:       l.Attempts,
+		DeadLetteredAt: l.DeadLetteredAt,
+	}
+
+	// commands from before their type was recorded are claims
+	if resp.Type == "" {
+		resp.Type = payment.TypeClaim
+	}
+
+	command, err := payment.Unmarshal(resp.Type, l.Payload)
+	if err != nil {
+		resp.CommandError = err.Error()
+		return resp, nil
+	}
+	resp.PaymentID = payment.ClaimID(command)
+
+	return resp, command
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/processor"
+)
+
+// The health of the service, by the states of the circuit breakers around its processors
+const (
+	HealthOK          = "ok"
+	HealthDegraded    = "degraded"
+	HealthUnavailable = "unavailable"
+)
+
+// Breaker reports the state of the circuit breaker around a processor, and when it changed to it
+type Breaker interface {
+	Name() string
+	State() (string, time.Time)
+}
+
+// HealthResponse is the health of the service returned to http callers
+type HealthResponse struct {
+	Status     string             `json:"status"`
+	Processors []*ProcessorHealth `json:"processors"`
+}
+
+// ProcessorHealth is the state of the circuit breaker around a processor
+type ProcessorHealth struct {
+	Name  string    `json:"name"`
+	State string    `json:"state"`
+	Since time.Time `json:"since"`
+}
+
+// Health serves the health of the service from the circuit breakers around its processors
+type Health struct {
+	Breakers []Breaker
+}
+
+// Get responds with the state of every breaker. The service is degraded when any breaker is not closed, and unavailable,
+// with a 503 Service Unavailable, when every breaker is open, as no payments can be processed
+func (h Health) Get(w http.ResponseWriter, r *http.Request) {
+
+	resp := &HealthResponse{Status: HealthOK, Processors: make([]*ProcessorHealth, 0, len(h.Breakers))}
+	open := 0
+
+	for _, b := range h.Breakers {
+		state, since := b.State()
+		resp.Processors = append(resp.Processors, &ProcessorHealth{Name: b.Name(), State: state, Since: since})
+		if state != processor.BreakerClosed {
+			resp.Status = HealthDegraded
+		}
+		if state == processor.BreakerOpen {
+			open++
+		}
+	}
+
+	if len(h.Breakers) > 0 && open == len(h.Breakers) {
+		resp.Status = HealthUnavailable
+		writeJSON(w, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// NewHealth is a factory for Health
+func NewHealth(breakers []Breaker) *Health {
+	return &Health{Breakers: breakers}
+}
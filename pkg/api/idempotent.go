@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
+)
+
+// replayedHeaders are the headers of a response which are stored so that they can be replayed
+var replayedHeaders = []string{"Content-Type", "Location", "Preference-Applied"}
+
+// storedResponse is a response remembered against an idempotency token so that it can be replayed
+type storedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header"`
+	Body   []byte            `json:"body"`
+}
+
+// Idempotent is middleware for the endpoints taking and acting on payments which makes sure a request is only acted on once
+// per idempotency token within the scope, such as pay for the pay and authorize endpoints. Repeats of a request are sent the
+// original response, and reuses of a token for a different request, or for the same request to a different endpoint or
+// payment, are rejected with a 409
+func Idempotent(keys idempotency.Store, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			b, err := readBody(r)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			var fields map[string]interface{}
+			if err := json.Unmarshal(b, &fields); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			token, _ := fields["idempotency_token"].(string)
+
+			// fingerprint the decoded request so that differences in formatting are not a conflict, along with
+			// the path so a token cannot be used both to pay and to authorise, or for the commands of two payments
+			canonical, err := json.Marshal(fields)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+				return
+			}
+			fingerprint := idempotency.Fingerprint(append([]byte(r.URL.Path+" "), canonical...))
+
+			key := scope + ":" + token
+
+			record, created, err := keys.Begin(key, fingerprint)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+				return
+			}
+
+			if !created {
+				replay(w, record, fingerprint)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			// forget requests which failed, or were abandoned, so that they can be made again
+			if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+				_ = keys.Release(key)
+				return
+			}
+
+			stored := &storedResponse{Status: rec.status, Header: make(map[string]string), Body: rec.body.Bytes()}
+			for _, h := range replayedHeaders {
+				if v := w.Header().Get(h); v != "" {
+					stored.Header[h] = v
+				}
+			}
+
+			response, err := json.Marshal(stored)
+			if err != nil {
+				_ = keys.Release(key)
+				return
+			}
+
+			_ = keys.Complete(key, response)
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, record *idempotency.Record, fingerprint string) {
+
+	if record.Fingerprint != fingerprint {
+		writeJSON(w, http.StatusConflict, &ErrorResponse{Error: "idempotency_token has already been used for a different request"})
+		return
+	}
+
+	if !record.Completed() {
+		writeJSON(w, http.StatusConflict, &ErrorResponse{Error: "a request with this idempotency_token is in progress"})
+		return
+	}
+
+	stored := &storedResponse{}
+	if err := json.Unmarshal(record.Response, stored); err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	for h, v := range stored.Header {
+		w.Header().Set(h, v)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(stored.Status)
+	_, _ = w.Write(stored.Body)
+}
+
+// responseRecorder keeps a copy of the response written through it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	rr.status = status
+	rr.ResponseWriter.WriteHeader(status)
+}
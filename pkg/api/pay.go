@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/handler"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// AwaitOutcome is middleware for the pay endpoint which responds with the Outcome of the Claim once it is known.
+// Callers sending "Prefer: respond-async", or whose Outcome is not known within the timeout, are sent a 202 instead
+func AwaitOutcome(awaiter *OutcomeAwaiter, timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			b, err := readBody(r)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			cr := &handler.ClaimRequest{}
+			if err := json.Unmarshal(b, cr); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			async := prefersAsync(r)
+
+			var outcomes <-chan *payment.Outcome
+			if !async {
+				var cancel func()
+				outcomes, cancel = awaiter.Register(cr.IdempotencyToken)
+				defer cancel()
+			}
+
+			rec := &statusRecorder{header: w.Header(), status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if rec.status != http.StatusOK {
+				w.WriteHeader(rec.status)
+				return
+			}
+
+			if async {
+				w.Header().Set("Preference-Applied", "respond-async")
+				writeAccepted(w, cr.IdempotencyToken)
+				return
+			}
+
+			select {
+			case outcome := <-outcomes:
+				writeJSON(w, http.StatusOK, paymentResponse(store.PaymentFromOutcome(outcome)))
+			case <-time.After(timeout):
+				writeAccepted(w, cr.IdempotencyToken)
+			case <-r.Context().Done():
+			}
+		})
+	}
+}
+
+// statusRecorder captures the status written by the wrapped handler so the response can be decided afterwards
+type statusRecorder struct {
+	header http.Header
+	status int
+}
+
+func (sr *statusRecorder) Header() http.Header {
+	return sr.header
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+}
+
+func prefersAsync(r *http.Request) bool {
+	for _, header := range r.Header["Prefer"] {
+		for _, pref := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeAccepted(w http.ResponseWriter, id string) {
+	w.Header().Set("Location", statusURL(id))
+	writeJSON(w, http.StatusAccepted, &PaymentResponse{ID: id, Status: payment.StatusReceived, StatusURL: statusURL(id)})
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// PaymentResponse is the representation of a payment returned to http callers
+type PaymentResponse struct {
+	ID              string          `json:"id"`
+	Payee           string          `json:"payee,omitempty"`
+	Status          string          `json:"status"`
+	VendorReference string          `json:"vendor_reference,omitempty"`
+	FailureCode     string          `json:"failure_code,omitempty"`
+	DeclineCode     string          `json:"decline_code,omitempty"`
+	FailureMessage  string          `json:"failure_message,omitempty"`
+	Amount          int64           `json:"amount,omitempty"`
+	Currency        string          `json:"currency,omitempty"`
+	AmountCaptured  int64           `json:"amount_captured,omitempty"`
+	AmountRefunded  int64           `json:"amount_refunded,omitempty"`
+	Processor       string          `json:"processor,omitempty"`
+	Card            *store.Card     `json:"card,omitempty"`
+	Risk            *store.Risk     `json:"risk,omitempty"`
+	NextAction      *store.Action   `json:"next_action,omitempty"`
+	Attempts        []store.Attempt `json:"attempts,omitempty"`
+	Refunds         []*store.Refund `json:"refunds,omitempty"`
+	VoidReason      string          `json:"void_reason,omitempty"`
+	Commands        []string        `json:"commands,omitempty"`
+	CreatedAt       *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time      `json:"updated_at,omitempty"`
+	StatusURL       string          `json:"status_url"`
+}
+
+// PaymentListResponse is a page of payments returned to http callers
+type PaymentListResponse struct {
+	Payments   []*PaymentResponse `json:"payments"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// ErrorResponse describes why a request failed
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Payments serves the status of payments from a Store
+type Payments struct {
+	Store store.Store
+}
+
+// Get responds with the payment identified by the id in the url
+func (p Payments) Get(w http.ResponseWriter, r *http.Request) {
+
+	found, err := p.Store.Get(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, paymentResponse(found))
+}
+
+// List responds with a page of the payments matching the query string parameters payee, status, from, to and cursor
+func (p Payments) List(w http.ResponseWriter, r *http.Request) {
+
+	params := r.URL.Query()
+
+	q := store.Query{
+		Payee:  params.Get("payee"),
+		Status: params.Get("status"),
+		Cursor: params.Get("cursor"),
+	}
+
+	var err error
+	if q.From, err = parseTime(params.Get("from")); err != nil {
+		writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: "from must be an RFC 3339 time"})
+		return
+	}
+	if q.To, err = parseTime(params.Get("to")); err != nil {
+		writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: "to must be an RFC 3339 time"})
+		return
+	}
+	if limit := params.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil {
+			writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: "limit must be a number"})
+			return
+		}
+	}
+
+	page, err := p.Store.List(q)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp := &PaymentListResponse{Payments: make([]*PaymentResponse, 0, len(page.Payments)), NextCursor: page.NextCursor}
+	for _, found := range page.Payments {
+		resp.Payments = append(resp.Payments, paymentResponse(found))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// NewPayments is a factory for Payments
+func NewPayments(s store.Store) *Payments {
+	return &Payments{Store: s}
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func statusURL(id string) string {
+	return "/payments/" + id
+}
+
+func paymentResponse(p *store.Payment) *PaymentResponse {
+	return &PaymentResponse{
+		ID:              p.ID,
+		Payee:           p.Payee,
+		Status:          p.Status,
+		VendorReference: p.VendorReference,
+		FailureCode:     p.FailureCode,
+		DeclineCode:     p.DeclineCode,
+		FailureMessage:  p.FailureMessage,
+		Amount:          p.Amount,
+		Currency:        p.Currency,
+		AmountCaptured:  p.AmountCaptured,
+		AmountRefunded:  p.AmountRefunded,
+		Processor:       p.Processor,
+		Card:            p.Card,
+		Risk:            p.Risk,
+		NextAction:      p.NextAction,
+		Attempts:        p.Attempts,
+		Refunds:         p.Refunds,
+		VoidReason:      p.VoidReason,
+		Commands:        p.Commands(),
+		CreatedAt:       &p.CreatedAt,
+		UpdatedAt:       &p.UpdatedAt,
+		StatusURL:       statusURL(p.ID),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
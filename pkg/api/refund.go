@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mannion007/payments-prototype/pkg/handler"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// ValidateRefundRequest is middleware for the refund endpoint which rejects refunds which cannot be made before they reach the bus.
+// Refunds of unknown payments are rejected with a 404, of payments with nothing left to refund with a 409,
+// and requests with invalid fields, or for more than is left to refund, with a 422
+func ValidateRefundRequest(payments store.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			rr := &handler.RefundRequest{}
+			if !decodeCommand(w, r, rr) {
+				return
+			}
+
+			p := findPayment(w, r, payments)
+			if p == nil {
+				return
+			}
+
+			if err := p.Allows(payment.TypeRefund); err != nil {
+				writeJSON(w, http.StatusConflict, &ErrorResponse{Error: err.Error()})
+				return
+			}
+
+			refundable := p.RefundableAmount()
+			if refundable <= 0 {
+				writeJSON(w, http.StatusConflict, &ErrorResponse{Error: fmt.Sprintf("payment is %s with nothing left to refund", p.Status)})
+				return
+			}
+
+			if rr.Amount > refundable {
+				ve := &handler.ValidationError{Errors: []handler.FieldError{{
+					Field:   "amount",
+					Code:    handler.CodeOutOfRange,
+					Message: fmt.Sprintf("must be no more than the %d left to refund", refundable),
+				}}}
+				writeJSON(w, http.StatusUnprocessableEntity, ve)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/handler"
+)
+
+// ValidateClaimRequest is middleware for the pay endpoint which rejects invalid requests before they reach the bus.
+// Malformed json is rejected with a 400, and requests with invalid fields with a 422 listing them
+func ValidateClaimRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		b, err := readBody(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: "failed to read request body"})
+			return
+		}
+
+		cr := &handler.ClaimRequest{}
+		if err := json.Unmarshal(b, cr); err != nil {
+			writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: "request body must be a json claim request"})
+			return
+		}
+
+		if err := cr.Validate(time.Now()); err != nil {
+			if ve, ok := err.(*handler.ValidationError); ok {
+				writeJSON(w, http.StatusUnprocessableEntity, ve)
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// readBody reads the body of a request, leaving it in place to be read again
+func readBody(r *http.Request) ([]byte, error) {
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
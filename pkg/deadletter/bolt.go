@@ -0,0 +1,121 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// lettersBucket holds every Letter, keyed by its id
+var lettersBucket = []byte("letters")
+
+// BoltStore is a Store which persists dead letters to a file using the embedded database bolt
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// Put adds a Letter, replacing any with the same id
+func (bs *BoltStore) Put(l *Letter) error {
+
+	b, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter, %s", err.Error())
+	}
+
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(lettersBucket).Put([]byte(l.ID), b)
+	})
+}
+
+// Get finds the Letter with the id
+func (bs *BoltStore) Get(id string) (*Letter, error) {
+
+	var l *Letter
+
+	err := bs.db.View(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket(lettersBucket).Get([]byte(id))
+		if b == nil {
+			return ErrNotFound
+		}
+
+		l = &Letter{}
+		if err := json.Unmarshal(b, l); err != nil {
+			return fmt.Errorf("failed to unmarshal dead letter, %s", err.Error())
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// List returns every Letter, the longest dead lettered first
+func (bs *BoltStore) List() ([]*Letter, error) {
+
+	var letters []*Letter
+
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(lettersBucket).ForEach(func(_, v []byte) error {
+			l := &Letter{}
+			if err := json.Unmarshal(v, l); err != nil {
+				return fmt.Errorf("failed to unmarshal dead letter, %s", err.Error())
+			}
+			letters = append(letters, l)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sortLetters(letters)
+
+	return letters, nil
+}
+
+// Delete removes the Letter with the id
+func (bs *BoltStore) Delete(id string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+
+		letters := tx.Bucket(lettersBucket)
+		if letters.Get([]byte(id)) == nil {
+			return ErrNotFound
+		}
+
+		return letters.Delete([]byte(id))
+	})
+}
+
+// Close releases the database file
+func (bs *BoltStore) Close() error {
+	return bs.db.Close()
+}
+
+// NewBoltStore is a factory for a BoltStore persisting to the file at path, which is created if needed
+func NewBoltStore(path string) (*BoltStore, error) {
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database, %s", err.Error())
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(lettersBucket)
+		return err
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bolt buckets, %s", err.Error())
+	}
+
+	return &BoltStore{db: db}, nil
+}
@@ -0,0 +1,98 @@
+package deadletter
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+)
+
+// The metadata keys describing why a message was dead lettered, along with the reason, topic and handler keys of watermill's poison queue
+const (
+	AttemptsKey       = "attempts_poisoned"
+	DeadLetteredAtKey = "poisoned_at"
+)
+
+// ErrNotFound is returned when a dead letter is not in the Store
+var ErrNotFound = errors.New("dead letter not found")
+
+// Letter is a message which could not be handled however many times it was attempted, kept so it can be inspected,
+// then published again once whatever it failed on is fixed, or discarded
+type Letter struct {
+	// ID is the uuid of the message
+	ID             string            `json:"id"`
+	Topic          string            `json:"topic"`
+	Handler        string            `json:"handler"`
+	Reason         string            `json:"reason"`
+	Attempts       int               `json:"attempts"`
+	Payload        []byte            `json:"payload"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	DeadLetteredAt time.Time         `json:"dead_lettered_at"`
+}
+
+// Message is the message the Letter was, without why it was dead lettered, so it can be published to its topic again
+func (l *Letter) Message() *message.Message {
+
+	msg := message.NewMessage(l.ID, l.Payload)
+	for k, v := range l.Metadata {
+		msg.Metadata.Set(k, v)
+	}
+
+	return msg
+}
+
+// Store defines the behaviour required to keep dead letters, keyed by the uuid of their message
+type Store interface {
+	Put(*Letter) error
+	// Get returns ErrNotFound when there is no Letter with the id
+	Get(id string) (*Letter, error)
+	// List returns every Letter, the longest dead lettered first
+	List() ([]*Letter, error)
+	// Delete returns ErrNotFound when there is no Letter with the id
+	Delete(id string) error
+	Close() error
+}
+
+// Mark records on a message why it was dead lettered, after how many attempts, by the handler of the topic
+func Mark(msg *message.Message, reason, topic, handler string, attempts int, at time.Time) {
+	msg.Metadata.Set(middleware.ReasonForPoisonedKey, reason)
+	msg.Metadata.Set(middleware.PoisonedTopicKey, topic)
+	msg.Metadata.Set(middleware.PoisonedHandlerKey, handler)
+	msg.Metadata.Set(AttemptsKey, strconv.Itoa(attempts))
+	msg.Metadata.Set(DeadLetteredAtKey, at.Format(time.RFC3339Nano))
+}
+
+// FromMessage is the Letter a message published to the topic of dead letters carries, reading why it was dead lettered
+// from its metadata, which the Letter keeps the rest of
+func FromMessage(msg *message.Message) *Letter {
+
+	l := &Letter{
+		ID:       msg.UUID,
+		Topic:    msg.Metadata.Get(middleware.PoisonedTopicKey),
+		Handler:  msg.Metadata.Get(middleware.PoisonedHandlerKey),
+		Reason:   msg.Metadata.Get(middleware.ReasonForPoisonedKey),
+		Payload:  msg.Payload,
+		Metadata: make(map[string]string, len(msg.Metadata)),
+	}
+
+	// messages dead lettered without these are still kept, with no attempts and the time they were received
+	l.Attempts, _ = strconv.Atoi(msg.Metadata.Get(AttemptsKey))
+	at, err := time.Parse(time.RFC3339Nano, msg.Metadata.Get(DeadLetteredAtKey))
+	if err != nil {
+		at = time.Now()
+	}
+	l.DeadLetteredAt = at
+
+	for k, v := range msg.Metadata {
+		switch k {
+		case middleware.ReasonForPoisonedKey, middleware.PoisonedTopicKey, middleware.PoisonedHandlerKey,
+			middleware.PoisonedSubscriberKey, AttemptsKey, DeadLetteredAtKey:
+			continue
+		}
+		l.Metadata[k] = v
+	}
+
+	return l
+}
@@ -0,0 +1,86 @@
+package deadletter
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a Store which keeps dead letters in memory, they are lost when the process exits
+type MemoryStore struct {
+	lock    sync.RWMutex
+	letters map[string]*Letter
+}
+
+// Put adds a Letter, replacing any with the same id
+func (ms *MemoryStore) Put(l *Letter) error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	ms.letters[l.ID] = l
+
+	return nil
+}
+
+// Get finds the Letter with the id
+func (ms *MemoryStore) Get(id string) (*Letter, error) {
+
+	ms.lock.RLock()
+	defer ms.lock.RUnlock()
+
+	l, ok := ms.letters[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return l, nil
+}
+
+// List returns every Letter, the longest dead lettered first
+func (ms *MemoryStore) List() ([]*Letter, error) {
+
+	ms.lock.RLock()
+	letters := make([]*Letter, 0, len(ms.letters))
+	for _, l := range ms.letters {
+		letters = append(letters, l)
+	}
+	ms.lock.RUnlock()
+
+	sortLetters(letters)
+
+	return letters, nil
+}
+
+// Delete removes the Letter with the id
+func (ms *MemoryStore) Delete(id string) error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	if _, ok := ms.letters[id]; !ok {
+		return ErrNotFound
+	}
+	delete(ms.letters, id)
+
+	return nil
+}
+
+// Close does nothing, there is nothing to release
+func (ms *MemoryStore) Close() error {
+	return nil
+}
+
+// sortLetters orders letters by when they were dead lettered, then by id
+func sortLetters(letters []*Letter) {
+	sort.Slice(letters, func(i, j int) bool {
+		if !letters[i].DeadLetteredAt.Equal(letters[j].DeadLetteredAt) {
+			return letters[i].DeadLetteredAt.Before(letters[j].DeadLetteredAt)
+		}
+		return letters[i].ID < letters[j].ID
+	})
+}
+
+// NewMemoryStore is a factory for an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{letters: make(map[string]*Letter)}
+}
@@ -0,0 +1,163 @@
+package eventstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// streamsBucket holds a bucket for each stream, with its records keyed by version
+	streamsBucket = []byte("streams")
+	// logBucket indexes every record by the order it was appended, to the key of its stream and version
+	logBucket = []byte("log")
+)
+
+// BoltStore is a Store which persists streams to a file using the embedded database bolt
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// Append adds records to the end of a stream, if it is at the expected version
+func (bs *BoltStore) Append(streamID string, expectedVersion int64, records ...*Record) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+
+		stream, err := tx.Bucket(streamsBucket).CreateBucketIfNotExists([]byte(streamID))
+		if err != nil {
+			return fmt.Errorf("failed to create stream bucket, %s", err.Error())
+		}
+
+		if actual := streamVersion(stream); actual != expectedVersion {
+			return &ConcurrencyError{StreamID: streamID, Expected: expectedVersion, Actual: actual}
+		}
+
+		stamp(streamID, expectedVersion, records, time.Now())
+
+		log := tx.Bucket(logBucket)
+
+		for _, r := range records {
+
+			b, err := json.Marshal(r)
+			if err != nil {
+				return fmt.Errorf("failed to marshal record, %s", err.Error())
+			}
+
+			if err := stream.Put(uint64Key(uint64(r.Version)), b); err != nil {
+				return err
+			}
+
+			seq, err := log.NextSequence()
+			if err != nil {
+				return err
+			}
+
+			ref := append(uint64Key(uint64(r.Version)), streamID...)
+			if err := log.Put(uint64Key(seq), ref); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Load reads every record in a stream, in order
+func (bs *BoltStore) Load(streamID string) ([]*Record, error) {
+
+	var records []*Record
+
+	err := bs.db.View(func(tx *bolt.Tx) error {
+
+		stream := tx.Bucket(streamsBucket).Bucket([]byte(streamID))
+		if stream == nil {
+			return nil
+		}
+
+		return stream.ForEach(func(_, v []byte) error {
+			r := &Record{}
+			if err := json.Unmarshal(v, r); err != nil {
+				return fmt.Errorf("failed to unmarshal record, %s", err.Error())
+			}
+			records = append(records, r)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// All passes every record in every stream to a func, in the order they were appended
+func (bs *BoltStore) All(fn func(*Record) error) error {
+	return bs.db.View(func(tx *bolt.Tx) error {
+
+		streams := tx.Bucket(streamsBucket)
+
+		return tx.Bucket(logBucket).ForEach(func(_, ref []byte) error {
+
+			stream := streams.Bucket(ref[8:])
+			if stream == nil {
+				return fmt.Errorf("stream %s is missing", ref[8:])
+			}
+
+			r := &Record{}
+			if err := json.Unmarshal(stream.Get(ref[:8]), r); err != nil {
+				return fmt.Errorf("failed to unmarshal record, %s", err.Error())
+			}
+
+			return fn(r)
+		})
+	})
+}
+
+// Close releases the database file
+func (bs *BoltStore) Close() error {
+	return bs.db.Close()
+}
+
+// streamVersion is the version of the last record in a stream bucket
+func streamVersion(stream *bolt.Bucket) int64 {
+	k, _ := stream.Cursor().Last()
+	if k == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(k))
+}
+
+// uint64Key encodes a number as a key which bolt orders numerically
+func uint64Key(n uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, n)
+	return key
+}
+
+// NewBoltStore is a factory for a BoltStore persisting to the file at path, which is created if needed
+func NewBoltStore(path string) (*BoltStore, error) {
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database, %s", err.Error())
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{streamsBucket, logBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bolt buckets, %s", err.Error())
+	}
+
+	return &BoltStore{db: db}, nil
+}
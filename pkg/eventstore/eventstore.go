@@ -0,0 +1,54 @@
+package eventstore
+
+import (
+	"fmt"
+	"time"
+)
+
+// Record is a command or event in the stream of a payment
+type Record struct {
+	// ID is the uuid of the message which carried the command or event
+	ID       string `json:"id"`
+	StreamID string `json:"stream_id"`
+	// Version is the position of the Record in its stream, starting from 1
+	Version    int64             `json:"version"`
+	Type       string            `json:"type"`
+	Payload    []byte            `json:"payload"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	RecordedAt time.Time         `json:"recorded_at"`
+}
+
+// ConcurrencyError is returned when records are appended to a stream which has changed since it was loaded
+type ConcurrencyError struct {
+	StreamID string
+	Expected int64
+	Actual   int64
+}
+
+func (ce *ConcurrencyError) Error() string {
+	return fmt.Sprintf("stream %s is at version %d, not the expected %d", ce.StreamID, ce.Actual, ce.Expected)
+}
+
+// Store defines the behaviour required to persist append only streams of records, keyed by the ID of the Claim of a payment.
+// Records are only appended to a stream at the version it was expected to be at, otherwise a ConcurrencyError is returned
+type Store interface {
+	Append(streamID string, expectedVersion int64, records ...*Record) error
+	Load(streamID string) ([]*Record, error)
+	// All passes every record in every stream to a func, in the order they were appended, stopping at the first error
+	All(func(*Record) error) error
+	Close() error
+}
+
+// version is the version of a stream made up of records
+func version(records []*Record) int64 {
+	return int64(len(records))
+}
+
+// stamp numbers records from a version of their stream, recording when they were appended
+func stamp(streamID string, from int64, records []*Record, now time.Time) {
+	for i, r := range records {
+		r.StreamID = streamID
+		r.Version = from + int64(i) + 1
+		r.RecordedAt = now
+	}
+}
@@ -0,0 +1,69 @@
+package eventstore
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store which keeps streams in memory, they are lost when the process exits
+type MemoryStore struct {
+	lock    sync.RWMutex
+	streams map[string][]*Record
+	log     []*Record
+}
+
+// Append adds records to the end of a stream, if it is at the expected version
+func (ms *MemoryStore) Append(streamID string, expectedVersion int64, records ...*Record) error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	stream := ms.streams[streamID]
+	if actual := version(stream); actual != expectedVersion {
+		return &ConcurrencyError{StreamID: streamID, Expected: expectedVersion, Actual: actual}
+	}
+
+	stamp(streamID, expectedVersion, records, time.Now())
+
+	ms.streams[streamID] = append(stream, records...)
+	ms.log = append(ms.log, records...)
+
+	return nil
+}
+
+// Load reads every record in a stream, in order
+func (ms *MemoryStore) Load(streamID string) ([]*Record, error) {
+
+	ms.lock.RLock()
+	defer ms.lock.RUnlock()
+
+	return append([]*Record(nil), ms.streams[streamID]...), nil
+}
+
+// All passes every record in every stream to a func, in the order they were appended
+func (ms *MemoryStore) All(fn func(*Record) error) error {
+
+	ms.lock.RLock()
+	log := append([]*Record(nil), ms.log...)
+	ms.lock.RUnlock()
+
+	for _, r := range log {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close does nothing, there is nothing to release
+func (ms *MemoryStore) Close() error {
+	return nil
+}
+
+// NewMemoryStore is a factory for an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		streams: make(map[string][]*Record),
+	}
+}
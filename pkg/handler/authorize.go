@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// AuthorizePayment is a message handler which holds money on cards, to be captured or voided later
+type AuthorizePayment struct {
+	Authorizer  payment.Authorizer
+	Payments    store.Store
+	Idempotency idempotency.Store
+}
+
+// Process handles Authorize messages using an Authorizer, returning a resulting message and an error, if any.
+// A Claim is only ever given to the Authorizer once, redeliveries of it result in the original Outcome
+func (ap AuthorizePayment) Process(msg *message.Message) ([]*message.Message, error) {
+
+	var authorize payment.Authorize
+
+	err := proto.Unmarshal(msg.Payload, &authorize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message, %s", err)
+	}
+
+	if authorize.Claim == nil {
+		return nil, fmt.Errorf("authorize message has no claim")
+	}
+
+	return processClaim(ap.Payments, ap.Idempotency, msg, authorize.Claim, payment.TypeAuthorize, ap.Authorizer.Authorize)
+}
+
+// NewAuthorizePayment is a factory for the handler: AuthorizePayment
+func NewAuthorizePayment(authorizer payment.Authorizer, payments store.Store, idempotencyStore idempotency.Store) *AuthorizePayment {
+
+	handler := AuthorizePayment{
+		Authorizer:  authorizer,
+		Payments:    payments,
+		Idempotency: idempotencyStore,
+	}
+
+	return &handler
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// autoAbandonNamespace derives the id of the Confirm which abandons a payment from the payment, so that every
+// instance of the job abandoning the same payment sends the same command and it is only acted on once
+var autoAbandonNamespace = uuid.MustParse("c3e1a7d4-2b9f-4c6e-8d05-7f1a9b3e6c42")
+
+// AutoAbandon fails payments whose payer has not taken the action they required within the timeout,
+// once the processor has been checked in case the action was taken
+type AutoAbandon struct {
+	Payments  store.Store
+	Publisher message.Publisher
+	Topic     string
+	Timeout   time.Duration
+}
+
+// Run publishes a Confirm command which abandons every payment requiring action for longer than the timeout before now, returning an error, if any
+func (aa AutoAbandon) Run(now time.Time) error {
+
+	q := store.Query{
+		Status: payment.StatusRequiresAction,
+		To:     now.Add(-aa.Timeout),
+	}
+
+	return forEach(aa.Payments, q, aa.abandon)
+}
+
+func (aa AutoAbandon) abandon(p *store.Payment) error {
+
+	confirm := &payment.Confirm{
+		ID:      uuid.NewSHA1(autoAbandonNamespace, []byte(p.ID)).String(),
+		ClaimID: p.ID,
+		Abandon: true,
+	}
+
+	buf, err := proto.Marshal(confirm)
+	if err != nil {
+		return fmt.Errorf("failed to marshal confirm, %s", err.Error())
+	}
+
+	err = aa.Publisher.Publish(aa.Topic, NewMessage(payment.TypeConfirm, buf))
+	if err != nil {
+		return fmt.Errorf("failed to publish confirm, %s", err.Error())
+	}
+
+	return nil
+}
+
+// Every runs the AutoAbandon at an interval until the context is done
+func (aa AutoAbandon) Every(ctx context.Context, interval time.Duration, logger watermill.LoggerAdapter) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := aa.Run(now); err != nil {
+				logger.Error("failed to abandon payments requiring action", err, nil)
+			}
+		}
+	}
+}
+
+// NewAutoAbandon is a factory for an AutoAbandon which publishes Confirm commands to a topic
+func NewAutoAbandon(payments store.Store, publisher message.Publisher, topic string, timeout time.Duration) *AutoAbandon {
+
+	aa := AutoAbandon{
+		Payments:  payments,
+		Publisher: publisher,
+		Topic:     topic,
+		Timeout:   timeout,
+	}
+
+	return &aa
+}
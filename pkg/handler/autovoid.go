@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// AutoVoidReason is the reason given for voiding authorisations which were held for too long
+const AutoVoidReason = "hold_expired"
+
+// autoVoidNamespace derives the id of the Void of an expired authorisation from its payment, so that every
+// instance of the job voiding the same authorisation sends the same command and it is only acted on once
+var autoVoidNamespace = uuid.MustParse("5b0c4c52-6f1e-4a53-9a1f-3e4d2a7c8b90")
+
+// AutoVoid voids authorisations which have not been captured within the hold window, releasing the money back to the payer
+type AutoVoid struct {
+	Payments  store.Store
+	Publisher message.Publisher
+	Topic     string
+	Hold      time.Duration
+}
+
+// Run publishes a Void command for every payment authorised longer than the hold window before now, returning an error, if any.
+// Payments whose Void has already failed are left to be looked into, as it would only fail again
+func (av AutoVoid) Run(now time.Time) error {
+
+	q := store.Query{
+		Status:           payment.StatusAuthorised,
+		AuthorisedBefore: now.Add(-av.Hold),
+	}
+
+	return forEach(av.Payments, q, av.void)
+}
+
+func (av AutoVoid) void(p *store.Payment) error {
+
+	void := &payment.Void{
+		ID:      uuid.NewSHA1(autoVoidNamespace, []byte(p.ID)).String(),
+		ClaimID: p.ID,
+		Reason:  AutoVoidReason,
+	}
+
+	if p.VoidFailed(void.ID) {
+		return nil
+	}
+
+	buf, err := proto.Marshal(void)
+	if err != nil {
+		return fmt.Errorf("failed to marshal void, %s", err.Error())
+	}
+
+	err = av.Publisher.Publish(av.Topic, NewMessage(payment.TypeVoid, buf))
+	if err != nil {
+		return fmt.Errorf("failed to publish void, %s", err.Error())
+	}
+
+	return nil
+}
+
+// forEach calls f with every payment matching the Query, a page at a time, returning an error, if any
+func forEach(payments store.Store, q store.Query, f func(*store.Payment) error) error {
+
+	q.Limit = store.MaxLimit
+
+	for {
+		page, err := payments.List(q)
+		if err != nil {
+			return fmt.Errorf("failed to list %s payments, %s", q.Status, err.Error())
+		}
+
+		for _, p := range page.Payments {
+			if err := f(p); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		q.Cursor = page.NextCursor
+	}
+}
+
+// Every runs the AutoVoid at an interval until the context is done
+func (av AutoVoid) Every(ctx context.Context, interval time.Duration, logger watermill.LoggerAdapter) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := av.Run(now); err != nil {
+				logger.Error("failed to void expired authorisations", err, nil)
+			}
+		}
+	}
+}
+
+// NewAutoVoid is a factory for an AutoVoid which publishes Void commands to a topic
+func NewAutoVoid(payments store.Store, publisher message.Publisher, topic string, hold time.Duration) *AutoVoid {
+
+	av := AutoVoid{
+		Payments:  payments,
+		Publisher: publisher,
+		Topic:     topic,
+		Hold:      hold,
+	}
+
+	return &av
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CapturePayment is a message handler which takes money held by authorised payments
+type CapturePayment struct {
+	Authorizer  payment.Authorizer
+	Payments    store.Store
+	Idempotency idempotency.Store
+}
+
+// Process handles Capture messages using an Authorizer, returning a resulting message and an error, if any.
+// A Capture is only ever given to the Authorizer once, and never for more than the payment authorised
+func (cp CapturePayment) Process(msg *message.Message) ([]*message.Message, error) {
+
+	var capture payment.Capture
+
+	err := proto.Unmarshal(msg.Payload, &capture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message, %s", err)
+	}
+
+	return once(cp.Idempotency, "capture:"+capture.ID, msg, func() (string, []byte, error) {
+
+		p, err := cp.Payments.Get(capture.ClaimID)
+		if err == store.ErrNotFound {
+			return captureFailed(&capture, "payment_not_found", "there is no payment to capture")
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get payment, %s", err)
+		}
+
+		err = p.Capture(&capture)
+		if failure, ok := err.(*payment.Failure); ok {
+			return captureFailed(&capture, failure.Code, failure.Message)
+		}
+		if err != nil {
+			return captureFailed(&capture, "payment_not_capturable", err.Error())
+		}
+
+		captured, err := cp.Authorizer.Capture(msg.Context(), &capture)
+		if err != nil && payment.IsRetryable(err) {
+			return "", nil, retryable("error when capturing", err)
+		}
+		if failure, ok := err.(*payment.Failure); ok {
+			return captureFailed(&capture, failure.Code, failure.Message)
+		}
+		if err != nil {
+			return captureFailed(&capture, "processing_error", err.Error())
+		}
+
+		captured.CaptureId = capture.ID
+		captured.ClaimId = capture.ClaimID
+		if captured.Currency == "" {
+			captured.Currency = p.Currency
+		}
+		captured.ProcessedAt = timestamppb.Now()
+
+		// record the capture straight away, so the payment cannot be captured or voided again
+		p.ApplyCapture(captured)
+		err = cp.Payments.Save(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to save payment, %s", err)
+		}
+
+		payload, err := proto.Marshal(captured)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+		}
+
+		return payment.TypeCaptured, payload, nil
+	})
+}
+
+func captureFailed(capture *payment.Capture, code, msg string) (string, []byte, error) {
+
+	failed := &payment.CaptureFailed{
+		CaptureId:      capture.ID,
+		ClaimId:        capture.ClaimID,
+		Amount:         capture.Amount,
+		FailureCode:    code,
+		FailureMessage: msg,
+		ProcessedAt:    timestamppb.Now(),
+	}
+
+	payload, err := proto.Marshal(failed)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+	}
+
+	return payment.TypeCaptureFailed, payload, nil
+}
+
+// NewCapturePayment is a factory for the handler: CapturePayment
+func NewCapturePayment(authorizer payment.Authorizer, payments store.Store, idempotencyStore idempotency.Store) *CapturePayment {
+
+	handler := CapturePayment{
+		Authorizer:  authorizer,
+		Payments:    payments,
+		Idempotency: idempotencyStore,
+	}
+
+	return &handler
+}
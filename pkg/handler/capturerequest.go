@@ -0,0 +1,6 @@
+package handler
+
+type CaptureRequest struct {
+	IdempotencyToken string `json:"idempotency_token"`
+	Amount           int64  `json:"amount"`
+}
@@ -1,20 +1,29 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
-	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
 	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // ClaimPayment is a message handler which takes payments
 type ClaimPayment struct {
-	Processor payment.Processor
+	Processor   payment.Processor
+	Payments    store.Store
+	Idempotency idempotency.Store
 }
 
-//Process handles messages using a Processor, returning a resulting message and an error, if any
+//Process handles messages using a Processor, returning a resulting message and an error, if any.
+// A Claim is only ever given to the Processor once, redeliveries of it result in the original Outcome.
+// The Processor abandons the Claim when the context of the message is done, so that it can be retried
 func (tph ClaimPayment) Process(msg *message.Message) ([]*message.Message, error) {
 
 	var claim payment.Claim
@@ -24,26 +33,116 @@ func (tph ClaimPayment) Process(msg *message.Message) ([]*message.Message, error
 		return nil, fmt.Errorf("failed to unmarshal message, %s", err)
 	}
 
-	outcome, err := tph.Processor.Process(&claim)
-	if err != nil {
-		return nil, fmt.Errorf("error when processing message, %s", err)
+	return processClaim(tph.Payments, tph.Idempotency, msg, &claim, payment.TypeClaim, tph.Processor.Process)
+}
+
+// processClaim gives a Claim to a processing func once, returning a message with its Outcome and an error, if any.
+// The payment is pending while the Claim is processed, and a Claim for a payment which has already been processed fails.
+// Claims share their idempotency keys whether they are processed or authorised, as either way they are the same payment.
+// The processors a Claim was given to are kept on the pending payment when it is to be retried, and a Claim which reached
+// one of them is only given to that one again
+func processClaim(payments store.Store, keys idempotency.Store, msg *message.Message, claim *payment.Claim, messageType string, process func(context.Context, *payment.Claim) (*payment.Outcome, error)) ([]*message.Message, error) {
+
+	return once(keys, "claim:"+claim.ID, msg, func() (string, []byte, error) {
+
+		p, err := payments.Get(claim.ID)
+		if err == store.ErrNotFound {
+			p = store.NewPayment(claim, time.Now())
+		} else if err != nil {
+			return "", nil, fmt.Errorf("failed to get payment, %s", err)
+		}
+
+		var outcome *payment.Outcome
+		var previous []*payment.Outcome_Attempt
+
+		err = p.Process(messageType)
+		if err == nil {
+			if err := payments.Save(p); err != nil {
+				return "", nil, fmt.Errorf("failed to save payment, %s", err)
+			}
+			ctx := msg.Context()
+			if p.Processor != "" {
+				ctx = payment.WithReached(ctx, p.Processor)
+			}
+			previous = p.OutcomeAttempts()
+			outcome, err = process(ctx, claim)
+		} else {
+			err = &payment.Failure{Code: "payment_already_processed", Message: err.Error()}
+		}
+
+		if err != nil && payment.IsRetryable(err) {
+			var attempted *payment.AttemptedError
+			if errors.As(err, &attempted) {
+				p.ApplyAttempts(attempted.Attempts, attempted.Reached)
+				if err := payments.Save(p); err != nil {
+					return "", nil, fmt.Errorf("failed to save payment, %s", err)
+				}
+			}
+			return "", nil, retryable("error when processing message", err)
+		}
+
+		// trying again will not help, so the claim has failed
+		if err != nil {
+			outcome = failedOutcome(err)
+		}
+
+		outcome.ClaimId = claim.ID
+		if outcome.Currency == "" {
+			outcome.Currency = claim.Amount.GetCurrency()
+		}
+		outcome.Payee = claim.Payee
+		outcome.ProcessedAt = timestamppb.Now()
+		if len(previous) > 0 {
+			outcome.Attempts = append(previous, outcome.Attempts...)
+		}
+
+		// record the outcome straight away, so the payment is never left pending once it has been processed
+		if p.ApplyOutcome(outcome) {
+			if err := payments.Save(p); err != nil {
+				return "", nil, fmt.Errorf("failed to save payment, %s", err)
+			}
+		}
+
+		payload, err := proto.Marshal(outcome)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+		}
+
+		return payment.TypeOutcome, payload, nil
+	})
+}
+
+// failedOutcome is the Outcome of a Claim which could not be processed, and will not be if it is tried again
+func failedOutcome(err error) *payment.Outcome {
+
+	outcome := &payment.Outcome{
+		Success:        false,
+		Status:         payment.Outcome_ERROR,
+		FailureCode:    "processing_error",
+		FailureMessage: err.Error(),
 	}
 
-	payload, err := proto.Marshal(outcome)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+	if failure, ok := err.(*payment.Failure); ok {
+		outcome.FailureCode = failure.Code
+		outcome.FailureMessage = failure.Message
 	}
 
-	event := message.NewMessage(watermill.NewUUID(), payload)
+	return outcome
+}
 
-	return message.Messages{event}, nil
+// retryable describes a RetryableError from a processor with the action which failed, keeping it retryable so that the
+// command is redelivered rather than dead lettered
+func retryable(action string, err error) error {
+	return payment.Retryable(fmt.Errorf("%s, %w", action, errors.Unwrap(err)))
 }
 
 // NewClaimPayment is a fatory for the handler: TakePayment
-func NewClaimPayment(processor payment.Processor) *ClaimPayment {
+func NewClaimPayment(processor payment.Processor, payments store.Store, idempotencyStore idempotency.Store) *ClaimPayment {
 
 	handler := ClaimPayment{
-		Processor: processor,
+		Processor:   processor,
+		Payments:    payments,
+		Idempotency: idempotencyStore,
 	}
 
 	return &handler
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AbandonedFailureCode is the failure code of payments which failed because the payer did not take the action they required in time
+const AbandonedFailureCode = "authentication_abandoned"
+
+// ConfirmPayment is a message handler which resumes payments once the payer has taken the action they required
+type ConfirmPayment struct {
+	Confirmer   payment.Confirmer
+	Payments    store.Store
+	Idempotency idempotency.Store
+}
+
+// Process handles Confirm messages using a Confirmer, returning a message with the Outcome of the payment and an error, if any.
+// A payment which no longer requires action has already been resumed, so a Confirm of it results in no message.
+// The payment fails when a Confirm which abandons it finds the action has still not been taken
+func (cp ConfirmPayment) Process(msg *message.Message) ([]*message.Message, error) {
+
+	var confirm payment.Confirm
+
+	err := proto.Unmarshal(msg.Payload, &confirm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message, %s", err)
+	}
+
+	return once(cp.Idempotency, "confirm:"+confirm.ID, msg, func() (string, []byte, error) {
+
+		p, err := cp.Payments.Get(confirm.ClaimID)
+		if err == store.ErrNotFound {
+			return "", nil, nil
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get payment, %s", err)
+		}
+
+		if p.Confirm(&confirm) != nil {
+			return "", nil, nil
+		}
+
+		outcome, err := cp.Confirmer.Confirm(msg.Context(), &confirm)
+		if err != nil && payment.IsRetryable(err) {
+			return "", nil, retryable("error when confirming", err)
+		}
+
+		// trying again will not help, so the payment has failed
+		if err != nil {
+			outcome = failedOutcome(err)
+		}
+
+		if outcome.Status == payment.Outcome_REQUIRES_ACTION && confirm.Abandon {
+			err := cp.Confirmer.Abandon(msg.Context(), &confirm)
+			if err != nil && payment.IsRetryable(err) {
+				return "", nil, retryable("error when abandoning", err)
+			}
+			if err != nil {
+				return "", nil, fmt.Errorf("error when abandoning, %s", err)
+			}
+			outcome = &payment.Outcome{
+				VendorReference: outcome.VendorReference,
+				Success:         false,
+				Status:          payment.Outcome_DECLINED,
+				FailureCode:     AbandonedFailureCode,
+				FailureMessage:  "the payer did not take the action required in time",
+				Processor:       outcome.Processor,
+			}
+		}
+
+		outcome.ClaimId = confirm.ClaimID
+		if outcome.VendorReference == "" {
+			outcome.VendorReference = p.VendorReference
+		}
+		if outcome.Currency == "" {
+			outcome.Currency = p.Currency
+		}
+		outcome.Payee = p.Payee
+		outcome.ProcessedAt = timestamppb.Now()
+
+		// record the outcome straight away, so the payment cannot be confirmed again once it has been resumed
+		if p.ApplyOutcome(outcome) {
+			if err := cp.Payments.Save(p); err != nil {
+				return "", nil, fmt.Errorf("failed to save payment, %s", err)
+			}
+		}
+
+		payload, err := proto.Marshal(outcome)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+		}
+
+		return payment.TypeOutcome, payload, nil
+	})
+}
+
+// NewConfirmPayment is a factory for the handler: ConfirmPayment
+func NewConfirmPayment(confirmer payment.Confirmer, payments store.Store, idempotencyStore idempotency.Store) *ConfirmPayment {
+
+	handler := ConfirmPayment{
+		Confirmer:   confirmer,
+		Payments:    payments,
+		Idempotency: idempotencyStore,
+	}
+
+	return &handler
+}
@@ -0,0 +1,6 @@
+package handler
+
+type ConfirmRequest struct {
+	IdempotencyToken string `json:"idempotency_token"`
+	RedirectResult   string `json:"redirect_result,omitempty"`
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/mannion007/payments-prototype/pkg/deadletter"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// attemptsKey is the context key of the number of times a message has been attempted
+type attemptsKey struct{}
+
+// DeadLetter is middleware for the handler of commands which, once a command has failed on each of its attempts, publishes
+// it to the topic of dead letters with why it failed, how often, and by which handler, then treats it as handled rather
+// than it being redelivered forever. Commands which failed with a RetryableError, such as while a processor is down, are
+// not dead letters, they are redelivered after a delay instead. It goes inside the middleware which retries, so it sees
+// every attempt, and recovers from panics itself, so a command which panics on every attempt is dead lettered too
+func DeadLetter(publisher message.Publisher, topic string, attempts int, redeliverAfter time.Duration) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+
+			// retries hand over the same message, so the count is kept in its context
+			attempt, ok := msg.Context().Value(attemptsKey{}).(*int)
+			if !ok {
+				attempt = new(int)
+				msg.SetContext(context.WithValue(msg.Context(), attemptsKey{}, attempt))
+			}
+			*attempt++
+
+			produced, err := recovered(h, msg)
+			if err == nil || *attempt < attempts {
+				return produced, err
+			}
+
+			if payment.IsRetryable(err) {
+				select {
+				case <-time.After(redeliverAfter):
+				case <-msg.Context().Done():
+				}
+				return nil, err
+			}
+
+			letter := msg.Copy()
+			maskLetter(letter)
+			deadletter.Mark(
+				letter,
+				err.Error(),
+				message.SubscribeTopicFromCtx(msg.Context()),
+				message.HandlerNameFromCtx(msg.Context()),
+				*attempt,
+				time.Now(),
+			)
+
+			if pubErr := publisher.Publish(topic, letter); pubErr != nil {
+				return nil, fmt.Errorf("%s, and failed to publish it to the dead letters, %s", err.Error(), pubErr.Error())
+			}
+
+			return nil, nil
+		}
+	}
+}
+
+// maskLetter masks the number of the card in the payload of a command before it is dead lettered, as dead letters are
+// kept until they are dealt with. A payload which cannot be decoded is left as it is
+func maskLetter(msg *message.Message) {
+
+	decoded, err := payment.Unmarshal(MessageType(msg, payment.TypeClaim), msg.Payload)
+	if err != nil {
+		return
+	}
+
+	if payload, err := mask(decoded, msg.Payload); err == nil {
+		msg.Payload = payload
+	}
+}
+
+// recovered handles the message, returning an error in place of a panic
+func recovered(h message.HandlerFunc, msg *message.Message) (produced []*message.Message, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic occurred, %v, stack: %s", r, debug.Stack())
+		}
+	}()
+
+	return h(msg)
+}
+
+// RecordDeadLetter is a message handler which keeps the messages published to the topic of dead letters in a Store
+type RecordDeadLetter struct {
+	Store deadletter.Store
+}
+
+// Process saves the dead letter carried by a message, with the number of any card in it masked, returning an error, if any
+func (rd RecordDeadLetter) Process(msg *message.Message) error {
+
+	maskLetter(msg)
+
+	if err := rd.Store.Put(deadletter.FromMessage(msg)); err != nil {
+		return fmt.Errorf("failed to save dead letter, %s", err.Error())
+	}
+
+	return nil
+}
+
+// NewRecordDeadLetter is a factory for RecordDeadLetter
+func NewRecordDeadLetter(s deadletter.Store) *RecordDeadLetter {
+	return &RecordDeadLetter{Store: s}
+}
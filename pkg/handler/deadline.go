@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// Deadline is middleware which gives each attempt at handling a message a deadline, after which the work it started,
+// such as requests to a processor, is abandoned. Each retry of the message is given a fresh deadline
+func Deadline(timeout time.Duration) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+
+			parent := msg.Context()
+			defer msg.SetContext(parent)
+
+			ctx, cancel := context.WithTimeout(parent, timeout)
+			defer cancel()
+
+			msg.SetContext(ctx)
+
+			return h(msg)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// Dispatcher is a message handler which passes each message to the handler for its type,
+// so that many types of command can share a topic
+type Dispatcher struct {
+	handlers map[string]message.HandlerFunc
+	fallback string
+}
+
+// Handle registers the handler for a type of message
+func (d *Dispatcher) Handle(messageType string, h message.HandlerFunc) *Dispatcher {
+	d.handlers[messageType] = h
+	return d
+}
+
+// Process passes a message to the handler for its type, returning an error if there is none
+func (d *Dispatcher) Process(msg *message.Message) ([]*message.Message, error) {
+
+	messageType := MessageType(msg, d.fallback)
+
+	h, ok := d.handlers[messageType]
+	if !ok {
+		return nil, fmt.Errorf("no handler for message type %s", messageType)
+	}
+
+	return h(msg)
+}
+
+// NewDispatcher is a factory for a Dispatcher, messages without a type are assumed to be of the fallback type
+func NewDispatcher(fallback string) *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[string]message.HandlerFunc),
+		fallback: fallback,
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// work carries out a command, returning the type and payload of the resulting event and an error, if any.
+// Work which results in no event returns an empty type
+type work func() (string, []byte, error)
+
+// once carries out a command at most once per idempotency key. Repeats of the command publish the original event again,
+// and when the work returns an error, or panics, the key is released so that the command can be retried
+func once(keys idempotency.Store, key string, cmd *message.Message, w work) ([]*message.Message, error) {
+
+	record, created, err := keys.Begin(key, idempotency.Fingerprint(cmd.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check idempotency of %s, %s", key, err)
+	}
+
+	if !created {
+		return replayEvent(cmd, record)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = keys.Release(key)
+			panic(r)
+		}
+	}()
+
+	eventType, payload, err := w()
+	if err != nil {
+		_ = keys.Release(key)
+		return nil, err
+	}
+
+	var events message.Messages
+	var eventUUID string
+	if eventType != "" {
+		event := newEvent(cmd, eventType, payload)
+		events = append(events, event)
+		eventUUID = event.UUID
+	}
+
+	stored, err := encodeEvent(eventUUID, eventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	err = keys.Complete(key, stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record result of %s, %s", key, err.Error())
+	}
+
+	return events, nil
+}
+
+// replayEvent publishes the event already produced for a command again, returning an error if it has not been produced
+func replayEvent(cmd *message.Message, record *idempotency.Record) ([]*message.Message, error) {
+
+	if record.Fingerprint != idempotency.Fingerprint(cmd.Payload) {
+		return nil, fmt.Errorf("%s has already been requested with different details", record.Key)
+	}
+
+	if !record.Completed() {
+		// the command being processed elsewhere may yet fail, or be abandoned, so it is tried again
+		return nil, payment.Retryable(fmt.Errorf("%s is already being processed", record.Key))
+	}
+
+	stored, err := decodeEvent(record.Response)
+	if err != nil {
+		return nil, err
+	}
+	if stored.Type == "" {
+		return nil, nil
+	}
+
+	// the event keeps its uuid, so it can be recognised as the same event when it is published again
+	event := newEvent(cmd, stored.Type, stored.Payload)
+	if stored.UUID != "" {
+		event.UUID = stored.UUID
+	}
+
+	return message.Messages{event}, nil
+}
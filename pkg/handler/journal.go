@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/eventstore"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// Journal is middleware for the handler of commands which appends each command it handles, and the events it results in,
+// to the stream of the payment in an event store, with the number of the card it was paid with masked. Anything already in the stream, such as a command redelivered and
+// the event published again for it, is not appended twice, and an append which loses a race fails so it can be retried
+func Journal(events eventstore.Store) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+
+			produced, err := h(msg)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := journal(events, msg, produced); err != nil {
+				return nil, err
+			}
+
+			return produced, nil
+		}
+	}
+}
+
+func journal(events eventstore.Store, cmd *message.Message, produced []*message.Message) error {
+
+	commandType := MessageType(cmd, payment.TypeClaim)
+
+	decoded, err := payment.Unmarshal(commandType, cmd.Payload)
+	if err != nil {
+		return err
+	}
+
+	streamID := payment.ClaimID(decoded)
+	if streamID == "" {
+		return fmt.Errorf("%s is not about a payment", commandType)
+	}
+
+	commandPayload, err := mask(decoded, cmd.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to mask %s, %s", commandType, err.Error())
+	}
+
+	stream, err := events.Load(streamID)
+	if err != nil {
+		return fmt.Errorf("failed to load stream %s, %s", streamID, err.Error())
+	}
+
+	recorded := make(map[string]bool, len(stream))
+	for _, r := range stream {
+		recorded[r.ID] = true
+	}
+
+	var records []*eventstore.Record
+	for _, msg := range append([]*message.Message{cmd}, produced...) {
+		if recorded[msg.UUID] {
+			continue
+		}
+		metadata := make(map[string]string, len(msg.Metadata))
+		for k, v := range msg.Metadata {
+			metadata[k] = v
+		}
+		payload := msg.Payload
+		if msg == cmd {
+			payload = commandPayload
+		}
+
+		records = append(records, &eventstore.Record{
+			ID:       msg.UUID,
+			Type:     MessageType(msg, commandType),
+			Payload:  payload,
+			Metadata: metadata,
+		})
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	err = events.Append(streamID, int64(len(stream)), records...)
+	if err != nil {
+		return fmt.Errorf("failed to append to stream %s, %s", streamID, err.Error())
+	}
+
+	return nil
+}
+
+// mask is the payload of a command with the number of the card of its Claim masked, as the history of a payment is kept
+// for good and rebuilding it only needs the ID, amount and payee of the Claim
+func mask(decoded proto.Message, payload []byte) ([]byte, error) {
+
+	if !payment.MaskCard(decoded) {
+		return payload, nil
+	}
+
+	return proto.Marshal(decoded)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+)
+
+// Logging is middleware which logs every message handled, and any error, along with its correlation id
+func Logging(logger watermill.LoggerAdapter) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+
+			fields := watermill.LogFields{
+				"handler":        message.HandlerNameFromCtx(msg.Context()),
+				"message_uuid":   msg.UUID,
+				"correlation_id": middleware.MessageCorrelationID(msg),
+			}
+
+			logger.Info("Handling message", fields)
+
+			produced, err := h(msg)
+			if err != nil {
+				logger.Error("Failed to handle message", err, fields)
+				return produced, err
+			}
+
+			logger.Info("Handled message", fields.Add(watermill.LogFields{"produced": len(produced)}))
+
+			return produced, nil
+		}
+	}
+}
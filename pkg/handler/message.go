@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// storedEvent is an event remembered against an idempotency key so that it can be published again
+type storedEvent struct {
+	UUID    string `json:"uuid,omitempty"`
+	Type    string `json:"type"`
+	Payload []byte `json:"payload"`
+}
+
+// MessageType is the type of protobuf message carried by a message,
+// messages from before the type was recorded are assumed to be of the fallback type
+func MessageType(msg *message.Message, fallback string) string {
+	if t := msg.Metadata.Get(payment.MessageTypeKey); t != "" {
+		return t
+	}
+	return fallback
+}
+
+// NewMessage creates a message carrying a protobuf message of the given type
+func NewMessage(messageType string, payload []byte) *message.Message {
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	msg.Metadata.Set(payment.MessageTypeKey, messageType)
+
+	return msg
+}
+
+// newEvent creates an event caused by a message, carrying its correlation id
+func newEvent(cause *message.Message, eventType string, payload []byte) *message.Message {
+
+	event := NewMessage(eventType, payload)
+	middleware.SetCorrelationID(middleware.MessageCorrelationID(cause), event)
+
+	return event
+}
+
+func encodeEvent(uuid, eventType string, payload []byte) ([]byte, error) {
+
+	b, err := json.Marshal(&storedEvent{UUID: uuid, Type: eventType, Payload: payload})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event, %s", err.Error())
+	}
+
+	return b, nil
+}
+
+func decodeEvent(b []byte) (*storedEvent, error) {
+
+	e := &storedEvent{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event, %s", err.Error())
+	}
+
+	return e, nil
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// RecordOutcome is a message handler which records the Outcome of payments, and of captures, voids and refunds of them, in a Store.
+// Voids which failed are recorded too, so that a void which failed is not made again by the AutoVoid.
+// Captures and voids are recorded by their handlers as they are made too, which this leaves alone as the state of the payment
+// no longer allows them, so the history of payments can be replayed through it to rebuild them
+type RecordOutcome struct {
+	Store store.Store
+}
+
+// Process saves the payment described by an event message, returning an error, if any
+func (ro RecordOutcome) Process(msg *message.Message) error {
+	switch MessageType(msg, payment.TypeOutcome) {
+	case payment.TypeOutcome:
+		return ro.outcome(msg)
+	case payment.TypeRefundSucceeded:
+		event := &payment.RefundSucceeded{}
+		if err := proto.Unmarshal(msg.Payload, event); err != nil {
+			return fmt.Errorf("failed to unmarshal message, %s", err)
+		}
+		return ro.refund(event.ClaimId, store.RefundFromSucceeded(event))
+	case payment.TypeRefundFailed:
+		event := &payment.RefundFailed{}
+		if err := proto.Unmarshal(msg.Payload, event); err != nil {
+			return fmt.Errorf("failed to unmarshal message, %s", err)
+		}
+		return ro.refund(event.ClaimId, store.RefundFromFailed(event))
+	case payment.TypeCaptured:
+		event := &payment.Captured{}
+		if err := proto.Unmarshal(msg.Payload, event); err != nil {
+			return fmt.Errorf("failed to unmarshal message, %s", err)
+		}
+		return ro.apply(event.ClaimId, func(p *store.Payment) bool { return p.ApplyCapture(event) })
+	case payment.TypeVoided:
+		event := &payment.Voided{}
+		if err := proto.Unmarshal(msg.Payload, event); err != nil {
+			return fmt.Errorf("failed to unmarshal message, %s", err)
+		}
+		return ro.apply(event.ClaimId, func(p *store.Payment) bool { return p.ApplyVoid(event) })
+	case payment.TypeVoidFailed:
+		event := &payment.VoidFailed{}
+		if err := proto.Unmarshal(msg.Payload, event); err != nil {
+			return fmt.Errorf("failed to unmarshal message, %s", err)
+		}
+		// voids of payments which are not known fail, and have nothing to record them on
+		if _, err := ro.Store.Get(event.ClaimId); err == store.ErrNotFound {
+			return nil
+		}
+		return ro.apply(event.ClaimId, func(p *store.Payment) bool { return p.ApplyVoidFailed(event) })
+	}
+
+	return nil
+}
+
+func (ro RecordOutcome) outcome(msg *message.Message) error {
+
+	var outcome payment.Outcome
+
+	err := proto.Unmarshal(msg.Payload, &outcome)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal message, %s", err)
+	}
+
+	p, err := ro.Store.Get(outcome.ClaimId)
+	if err == store.ErrNotFound {
+		p = store.PaymentFromOutcome(&outcome)
+	} else if err != nil {
+		return fmt.Errorf("failed to get payment, %s", err)
+	} else if !p.ApplyOutcome(&outcome) {
+		// an outcome published again is not allowed by the state of the payment, so cannot undo what was done since
+		return nil
+	}
+
+	err = ro.Store.Save(p)
+	if err != nil {
+		return fmt.Errorf("failed to save payment, %s", err)
+	}
+
+	return nil
+}
+
+func (ro RecordOutcome) refund(claimID string, r *store.Refund) error {
+	return ro.apply(claimID, func(p *store.Payment) bool { return p.ApplyRefund(r) })
+}
+
+// apply makes a change to a payment, only saving it if the change had not already been made
+func (ro RecordOutcome) apply(claimID string, change func(*store.Payment) bool) error {
+
+	p, err := ro.Store.Get(claimID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment, %s", err)
+	}
+
+	if !change(p) {
+		return nil
+	}
+
+	err = ro.Store.Save(p)
+	if err != nil {
+		return fmt.Errorf("failed to save payment, %s", err)
+	}
+
+	return nil
+}
+
+// NewRecordOutcome is a factory for the handler: RecordOutcome
+func NewRecordOutcome(s store.Store) *RecordOutcome {
+
+	handler := RecordOutcome{
+		Store: s,
+	}
+
+	return &handler
+}
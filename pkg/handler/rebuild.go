@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/mannion007/payments-prototype/pkg/eventstore"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+)
+
+// ErrNoHistory is returned by Rebuild when the event store has no history to rebuild the status Store from
+var ErrNoHistory = errors.New("there is no history of payments to rebuild from")
+
+// errFound stops looking through the event store once a record has been found
+var errFound = errors.New("found a record")
+
+// Rebuild empties the status Store, then replays the history of every payment from an event store into it, returning an error, if any.
+// The status Store is left alone, and ErrNoHistory returned, when the event store is empty
+func Rebuild(events eventstore.Store, payments store.Store) error {
+
+	err := events.All(func(*eventstore.Record) error { return errFound })
+	if err == nil {
+		return ErrNoHistory
+	}
+	if err != errFound {
+		return fmt.Errorf("failed to read the event store, %s", err.Error())
+	}
+
+	if err := payments.Truncate(); err != nil {
+		return fmt.Errorf("failed to truncate payments, %s", err.Error())
+	}
+
+	project := NewRecordOutcome(payments)
+
+	return events.All(func(r *eventstore.Record) error {
+
+		switch r.Type {
+		case payment.TypeClaim, payment.TypeAuthorize:
+			return receive(payments, r)
+		}
+
+		msg := message.NewMessage(r.ID, r.Payload)
+		for k, v := range r.Metadata {
+			msg.Metadata.Set(k, v)
+		}
+		msg.Metadata.Set(payment.MessageTypeKey, r.Type)
+
+		if err := project.Process(msg); err != nil {
+			return fmt.Errorf("failed to replay %s %d of %s, %s", r.Type, r.Version, r.StreamID, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// receive records the payment for a Claim as it was when the command to process it was handled
+func receive(payments store.Store, r *eventstore.Record) error {
+
+	decoded, err := payment.Unmarshal(r.Type, r.Payload)
+	if err != nil {
+		return err
+	}
+
+	claim, ok := decoded.(*payment.Claim)
+	if authorize, isAuthorize := decoded.(*payment.Authorize); isAuthorize {
+		claim, ok = authorize.Claim, authorize.Claim != nil
+	}
+	if !ok {
+		return fmt.Errorf("%s %d of %s has no claim", r.Type, r.Version, r.StreamID)
+	}
+
+	// a claim for a payment which was already processed did not change it
+	_, err = payments.Get(claim.ID)
+	if err == nil {
+		return nil
+	}
+	if err != store.ErrNotFound {
+		return fmt.Errorf("failed to get payment, %s", err)
+	}
+
+	p := store.NewPayment(claim, r.RecordedAt)
+	if err := p.Process(r.Type); err != nil {
+		return err
+	}
+
+	return payments.Save(p)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/mannion007/payments-prototype/pkg/eventstore"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRebuild(t *testing.T) {
+
+	authorize := func(id string) proto.Message {
+		return &payment.Authorize{Claim: &payment.Claim{
+			ID:     id,
+			Payee:  "fbc8fa45-9041-42ea-abe0-2dc9c7581123",
+			Amount: &payment.Claim_MonetaryAmount{Currency: "GBP", Value: 1000},
+		}}
+	}
+	authorised := func(id string) proto.Message {
+		return &payment.Outcome{ClaimId: id, Status: payment.Outcome_AUTHORISED, Amount: 1000, Currency: "GBP", ProcessedAt: timestamppb.Now()}
+	}
+
+	tests := []struct {
+		name   string
+		types  []string
+		status string
+	}{
+		{
+			name:   "authorised",
+			types:  []string{payment.TypeAuthorize, payment.TypeOutcome},
+			status: payment.StatusAuthorised,
+		},
+		{
+			name:   "captured",
+			types:  []string{payment.TypeAuthorize, payment.TypeOutcome, payment.TypeCapture, payment.TypeCaptured},
+			status: payment.StatusCaptured,
+		},
+		{
+			name:   "voided",
+			types:  []string{payment.TypeAuthorize, payment.TypeOutcome, payment.TypeVoid, payment.TypeVoided},
+			status: payment.StatusVoided,
+		},
+		{
+			name:   "captured then voided",
+			types:  []string{payment.TypeAuthorize, payment.TypeOutcome, payment.TypeCaptured, payment.TypeVoided},
+			status: payment.StatusCaptured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			id := uuid.New().String()
+			messages := map[string]proto.Message{
+				payment.TypeAuthorize: authorize(id),
+				payment.TypeOutcome:   authorised(id),
+				payment.TypeCapture:   &payment.Capture{ID: uuid.New().String(), ClaimID: id, Amount: 1000},
+				payment.TypeCaptured:  &payment.Captured{ClaimId: id, Amount: 1000, ProcessedAt: timestamppb.Now()},
+				payment.TypeVoid:      &payment.Void{ID: uuid.New().String(), ClaimID: id},
+				payment.TypeVoided:    &payment.Voided{ClaimId: id, Reason: "abandoned", ProcessedAt: timestamppb.Now()},
+			}
+
+			var records []*eventstore.Record
+			for _, messageType := range tt.types {
+				payload, err := proto.Marshal(messages[messageType])
+				if err != nil {
+					t.Fatal(err)
+				}
+				records = append(records, &eventstore.Record{ID: uuid.New().String(), Type: messageType, Payload: payload})
+			}
+
+			events := eventstore.NewMemoryStore()
+			if err := events.Append(id, 0, records...); err != nil {
+				t.Fatal(err)
+			}
+			payments := store.NewMemoryStore()
+
+			if err := Rebuild(events, payments); err != nil {
+				t.Fatalf("failed to rebuild, %s", err)
+			}
+
+			p, err := payments.Get(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Status != tt.status {
+				t.Fatalf("expected the payment to be rebuilt as %s, it is %s", tt.status, p.Status)
+			}
+		})
+	}
+}
+
+func TestRebuildWithoutHistory(t *testing.T) {
+
+	payments := store.NewMemoryStore()
+	p := store.NewPayment(&payment.Claim{ID: uuid.New().String()}, timestamppb.Now().AsTime())
+	if err := payments.Save(p); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Rebuild(eventstore.NewMemoryStore(), payments); err != ErrNoHistory {
+		t.Fatalf("expected ErrNoHistory, got %v", err)
+	}
+
+	if _, err := payments.Get(p.ID); err != nil {
+		t.Fatalf("expected the payments to be left alone, got %s", err)
+	}
+}
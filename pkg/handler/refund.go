@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RefundPayment is a message handler which gives back money taken by payments
+type RefundPayment struct {
+	Refunder    payment.Refunder
+	Payments    store.Store
+	Idempotency idempotency.Store
+}
+
+// Process handles Refund messages using a Refunder, returning a resulting message and an error, if any.
+// A Refund is only ever given to the Refunder once, and never for more than is left of what the payment captured
+func (rp RefundPayment) Process(msg *message.Message) ([]*message.Message, error) {
+
+	var refund payment.Refund
+
+	err := proto.Unmarshal(msg.Payload, &refund)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message, %s", err)
+	}
+
+	return once(rp.Idempotency, "refund:"+refund.ID, msg, func() (string, []byte, error) {
+
+		p, err := rp.Payments.Get(refund.ClaimID)
+		if err == store.ErrNotFound {
+			return refundFailed(&refund, "payment_not_found", "there is no payment to refund")
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get payment, %s", err)
+		}
+
+		err = p.Refund(&refund)
+		if failure, ok := err.(*payment.Failure); ok {
+			return refundFailed(&refund, failure.Code, failure.Message)
+		}
+		if err != nil {
+			return refundFailed(&refund, "payment_not_refundable", err.Error())
+		}
+
+		succeeded, err := rp.Refunder.Refund(msg.Context(), &refund)
+		if err != nil && payment.IsRetryable(err) {
+			return "", nil, retryable("error when refunding", err)
+		}
+		if failure, ok := err.(*payment.Failure); ok {
+			return refundFailed(&refund, failure.Code, failure.Message)
+		}
+		if err != nil {
+			return refundFailed(&refund, "processing_error", err.Error())
+		}
+
+		succeeded.RefundId = refund.ID
+		succeeded.ClaimId = refund.ClaimID
+		succeeded.TotalRefunded = p.AmountRefunded + succeeded.Amount
+		succeeded.ProcessedAt = timestamppb.Now()
+
+		// record the refund straight away, so a following refund cannot take the total over what was captured
+		p.ApplyRefund(store.RefundFromSucceeded(succeeded))
+		err = rp.Payments.Save(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to save payment, %s", err)
+		}
+
+		payload, err := proto.Marshal(succeeded)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+		}
+
+		return payment.TypeRefundSucceeded, payload, nil
+	})
+}
+
+func refundFailed(refund *payment.Refund, code, msg string) (string, []byte, error) {
+
+	failed := &payment.RefundFailed{
+		RefundId:       refund.ID,
+		ClaimId:        refund.ClaimID,
+		Amount:         refund.Amount,
+		FailureCode:    code,
+		FailureMessage: msg,
+		ProcessedAt:    timestamppb.Now(),
+	}
+
+	payload, err := proto.Marshal(failed)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+	}
+
+	return payment.TypeRefundFailed, payload, nil
+}
+
+// NewRefundPayment is a factory for the handler: RefundPayment
+func NewRefundPayment(refunder payment.Refunder, payments store.Store, idempotencyStore idempotency.Store) *RefundPayment {
+
+	handler := RefundPayment{
+		Refunder:    refunder,
+		Payments:    payments,
+		Idempotency: idempotencyStore,
+	}
+
+	return &handler
+}
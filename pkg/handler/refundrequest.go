@@ -0,0 +1,7 @@
+package handler
+
+type RefundRequest struct {
+	IdempotencyToken string `json:"idempotency_token"`
+	Amount           int64  `json:"amount"`
+	Reason           string `json:"reason"`
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// The codes describing why a field is invalid
+const (
+	CodeRequired    = "required"
+	CodeInvalid     = "invalid"
+	CodeExpired     = "expired"
+	CodeUnsupported = "unsupported"
+	CodeOutOfRange  = "out_of_range"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// FieldError describes why a field of a request is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a request has invalid fields
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (ve *ValidationError) Error() string {
+	fields := make([]string, 0, len(ve.Errors))
+	for _, fe := range ve.Errors {
+		fields = append(fields, fmt.Sprintf("%s: %s", fe.Field, fe.Message))
+	}
+	return "invalid request, " + strings.Join(fields, ", ")
+}
+
+func (ve *ValidationError) add(field, code, message string) {
+	ve.Errors = append(ve.Errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Validate checks a ClaimRequest can be made into a Claim at the given time, returning a ValidationError if not
+func (cr *ClaimRequest) Validate(now time.Time) error {
+
+	ve := &ValidationError{}
+
+	validateUUID(ve, "idempotency_token", cr.IdempotencyToken)
+	validateUUID(ve, "payee_id", cr.PayeeId)
+	validateAmount(ve, cr.Amount)
+	validateCard(ve, cr.Card, now)
+
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+
+	return nil
+}
+
+// refundReasons are the reasons a refund can be given for
+var refundReasons = map[string]bool{
+	"duplicate":             true,
+	"fraudulent":            true,
+	"requested_by_customer": true,
+}
+
+// Validate checks a RefundRequest can be made into a Refund, returning a ValidationError if not
+func (rr *RefundRequest) Validate() error {
+
+	ve := &ValidationError{}
+
+	validateUUID(ve, "idempotency_token", rr.IdempotencyToken)
+
+	if rr.Amount < 0 {
+		ve.add("amount", CodeOutOfRange, "must be a positive number of minor units, or left out to refund everything")
+	}
+
+	if rr.Reason != "" && !refundReasons[rr.Reason] {
+		ve.add("reason", CodeInvalid, "must be one of duplicate, fraudulent or requested_by_customer")
+	}
+
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+
+	return nil
+}
+
+// Validate checks a CaptureRequest can be made into a Capture, returning a ValidationError if not
+func (cr *CaptureRequest) Validate() error {
+
+	ve := &ValidationError{}
+
+	validateUUID(ve, "idempotency_token", cr.IdempotencyToken)
+
+	if cr.Amount < 0 {
+		ve.add("amount", CodeOutOfRange, "must be a positive number of minor units, or left out to capture everything")
+	}
+
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+
+	return nil
+}
+
+// Validate checks a ConfirmRequest can be made into a Confirm, returning a ValidationError if not
+func (cr *ConfirmRequest) Validate() error {
+
+	ve := &ValidationError{}
+
+	validateUUID(ve, "idempotency_token", cr.IdempotencyToken)
+
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+
+	return nil
+}
+
+// voidReasons are the reasons an authorisation can be voided for
+var voidReasons = map[string]bool{
+	"abandoned":             true,
+	"duplicate":             true,
+	"fraudulent":            true,
+	"requested_by_customer": true,
+}
+
+// Validate checks a VoidRequest can be made into a Void, returning a ValidationError if not
+func (vr *VoidRequest) Validate() error {
+
+	ve := &ValidationError{}
+
+	validateUUID(ve, "idempotency_token", vr.IdempotencyToken)
+
+	if vr.Reason != "" && !voidReasons[vr.Reason] {
+		ve.add("reason", CodeInvalid, "must be one of abandoned, duplicate, fraudulent or requested_by_customer")
+	}
+
+	if len(ve.Errors) > 0 {
+		return ve
+	}
+
+	return nil
+}
+
+func validateUUID(ve *ValidationError, field, value string) {
+	if value == "" {
+		ve.add(field, CodeRequired, "is required")
+		return
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		ve.add(field, CodeInvalid, "must be a uuid")
+	}
+}
+
+func validateAmount(ve *ValidationError, a Amount) {
+
+	var currency payment.Currency
+	var ok bool
+
+	switch {
+	case a.Currency == "":
+		ve.add("amount.currency", CodeRequired, "is required")
+	case !currencyCode.MatchString(a.Currency):
+		ve.add("amount.currency", CodeInvalid, "must be an ISO 4217 currency code")
+	default:
+		if currency, ok = payment.LookupCurrency(a.Currency); !ok {
+			ve.add("amount.currency", CodeUnsupported, fmt.Sprintf("%s is not supported", a.Currency))
+		}
+	}
+
+	if a.Value <= 0 {
+		ve.add("amount.value", CodeOutOfRange, "must be a positive number of minor units")
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := currency.Validate(a.Value); err != nil {
+		ve.add("amount.value", CodeOutOfRange, err.Error())
+	}
+}
+
+func validateCard(ve *ValidationError, c Card, now time.Time) {
+
+	switch {
+	case c.Number == "":
+		ve.add("card.number", CodeRequired, "is required")
+	case !luhn(c.Number):
+		ve.add("card.number", CodeInvalid, "is not a valid card number")
+	}
+
+	year, err := strconv.Atoi(c.Expiry.Year)
+	if err != nil || len(c.Expiry.Year) != 4 {
+		ve.add("card.expiry.year", CodeInvalid, "must be a four digit year")
+		return
+	}
+
+	month, err := strconv.Atoi(c.Expiry.Month)
+	if err != nil || month < 1 || month > 12 {
+		ve.add("card.expiry.month", CodeInvalid, "must be a month from 1 to 12")
+		return
+	}
+
+	// cards expire at the end of their expiry month
+	expiresAt := time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+	if !now.Before(expiresAt) {
+		ve.add("card.expiry", CodeExpired, "card has expired")
+	}
+}
+
+// luhn checks a card number is made of 12 to 19 digits with a valid Luhn check digit
+func luhn(number string) bool {
+
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			return false
+		}
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLuhn(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		number string
+		valid  bool
+	}{
+		{name: "visa", number: "4242424242424242", valid: true},
+		{name: "mastercard", number: "5555555555554444", valid: true},
+		{name: "amex", number: "378282246310005", valid: true},
+		{name: "twelve digits", number: "000000000000", valid: true},
+		{name: "nineteen digits", number: "6011000990139424009", valid: true},
+		{name: "wrong check digit", number: "4242424242424241"},
+		{name: "transposed digits", number: "4242424242422442"},
+		{name: "too short", number: "42424242426"},
+		{name: "too long", number: "42424242424242424242"},
+		{name: "spaces", number: "4242 4242 4242 4242"},
+		{name: "letters", number: "42424242424242a2"},
+		{name: "empty", number: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid := luhn(tt.number); valid != tt.valid {
+				t.Fatalf("expected luhn(%q) to be %t, got %t", tt.number, tt.valid, valid)
+			}
+		})
+	}
+}
+
+func TestValidateCard(t *testing.T) {
+
+	now := time.Date(2021, time.February, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		card   Card
+		errors []FieldError
+	}{
+		{
+			name: "valid",
+			card: Card{Number: "4242424242424242", Expiry: Expiry{Year: "2030", Month: "10"}},
+		},
+		{
+			name: "expires at the end of the current month",
+			card: Card{Number: "4242424242424242", Expiry: Expiry{Year: "2021", Month: "2"}},
+		},
+		{
+			name: "expired last month",
+			card: Card{Number: "4242424242424242", Expiry: Expiry{Year: "2021", Month: "01"}},
+			errors: []FieldError{
+				{Field: "card.expiry", Code: CodeExpired},
+			},
+		},
+		{
+			name: "expired last year",
+			card: Card{Number: "4242424242424242", Expiry: Expiry{Year: "2020", Month: "12"}},
+			errors: []FieldError{
+				{Field: "card.expiry", Code: CodeExpired},
+			},
+		},
+		{
+			name: "invalid number",
+			card: Card{Number: "4242424242424241", Expiry: Expiry{Year: "2030", Month: "10"}},
+			errors: []FieldError{
+				{Field: "card.number", Code: CodeInvalid},
+			},
+		},
+		{
+			name: "missing fields",
+			card: Card{},
+			errors: []FieldError{
+				{Field: "card.number", Code: CodeRequired},
+				{Field: "card.expiry.year", Code: CodeInvalid},
+			},
+		},
+		{
+			name: "missing month",
+			card: Card{Number: "4242424242424242", Expiry: Expiry{Year: "2030"}},
+			errors: []FieldError{
+				{Field: "card.expiry.month", Code: CodeInvalid},
+			},
+		},
+		{
+			name: "two digit year",
+			card: Card{Number: "4242424242424242", Expiry: Expiry{Year: "30", Month: "10"}},
+			errors: []FieldError{
+				{Field: "card.expiry.year", Code: CodeInvalid},
+			},
+		},
+		{
+			name: "thirteenth month",
+			card: Card{Number: "4242424242424242", Expiry: Expiry{Year: "2030", Month: "13"}},
+			errors: []FieldError{
+				{Field: "card.expiry.month", Code: CodeInvalid},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ve := &ValidationError{}
+			validateCard(ve, tt.card, now)
+
+			assertFieldErrors(t, tt.errors, ve)
+		})
+	}
+}
+
+func TestClaimRequestValidate(t *testing.T) {
+
+	now := time.Date(2021, time.February, 15, 12, 0, 0, 0, time.UTC)
+
+	valid := func() ClaimRequest {
+		return ClaimRequest{
+			IdempotencyToken: "3f9a2c7e-1d4b-4e8f-b6a5-0c2d9e8f7a13",
+			PayeeId:          "fbc8fa45-9041-42ea-abe0-2dc9c7581123",
+			Amount:           Amount{Currency: "GBP", Value: 1000},
+			Card:             Card{Number: "4242424242424242", Expiry: Expiry{Year: "2030", Month: "10"}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(cr *ClaimRequest)
+		errors []FieldError
+	}{
+		{
+			name:   "valid",
+			change: func(cr *ClaimRequest) {},
+		},
+		{
+			name:   "missing idempotency token",
+			change: func(cr *ClaimRequest) { cr.IdempotencyToken = "" },
+			errors: []FieldError{{Field: "idempotency_token", Code: CodeRequired}},
+		},
+		{
+			name:   "payee which is not a uuid",
+			change: func(cr *ClaimRequest) { cr.PayeeId = "payee" },
+			errors: []FieldError{{Field: "payee_id", Code: CodeInvalid}},
+		},
+		{
+			name:   "missing amount",
+			change: func(cr *ClaimRequest) { cr.Amount = Amount{} },
+			errors: []FieldError{
+				{Field: "amount.currency", Code: CodeRequired},
+				{Field: "amount.value", Code: CodeOutOfRange},
+			},
+		},
+		{
+			name:   "missing card",
+			change: func(cr *ClaimRequest) { cr.Card = Card{} },
+			errors: []FieldError{
+				{Field: "card.number", Code: CodeRequired},
+				{Field: "card.expiry.year", Code: CodeInvalid},
+			},
+		},
+		{
+			name:   "missing everything",
+			change: func(cr *ClaimRequest) { *cr = ClaimRequest{} },
+			errors: []FieldError{
+				{Field: "idempotency_token", Code: CodeRequired},
+				{Field: "payee_id", Code: CodeRequired},
+				{Field: "amount.currency", Code: CodeRequired},
+				{Field: "amount.value", Code: CodeOutOfRange},
+				{Field: "card.number", Code: CodeRequired},
+				{Field: "card.expiry.year", Code: CodeInvalid},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			cr := valid()
+			tt.change(&cr)
+
+			err := cr.Validate(now)
+			if len(tt.errors) == 0 {
+				if err != nil {
+					t.Fatalf("expected the request to be valid, got %s", err)
+				}
+				return
+			}
+
+			ve, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			assertFieldErrors(t, tt.errors, ve)
+		})
+	}
+}
+
+// assertFieldErrors checks the fields of a ValidationError are invalid for the expected codes, in order, whatever their messages
+func assertFieldErrors(t *testing.T, expected []FieldError, ve *ValidationError) {
+
+	t.Helper()
+
+	got := make([]FieldError, 0, len(ve.Errors))
+	for _, fe := range ve.Errors {
+		got = append(got, FieldError{Field: fe.Field, Code: fe.Code})
+	}
+	if expected == nil {
+		expected = []FieldError{}
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/golang/protobuf/proto"
+	"github.com/mannion007/payments-prototype/pkg/idempotency"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"github.com/mannion007/payments-prototype/pkg/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// VoidPayment is a message handler which releases money held by authorised payments
+type VoidPayment struct {
+	Authorizer  payment.Authorizer
+	Payments    store.Store
+	Idempotency idempotency.Store
+}
+
+// Process handles Void messages using an Authorizer, returning a resulting message and an error, if any.
+// A Void is only ever given to the Authorizer once, and only for payments which are still authorised
+func (vp VoidPayment) Process(msg *message.Message) ([]*message.Message, error) {
+
+	var void payment.Void
+
+	err := proto.Unmarshal(msg.Payload, &void)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message, %s", err)
+	}
+
+	return once(vp.Idempotency, "void:"+void.ID, msg, func() (string, []byte, error) {
+
+		p, err := vp.Payments.Get(void.ClaimID)
+		if err == store.ErrNotFound {
+			return voidFailed(&void, "payment_not_found", "there is no payment to void")
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get payment, %s", err)
+		}
+
+		if err := p.Void(&void); err != nil {
+			return voidFailed(&void, "payment_not_voidable", err.Error())
+		}
+
+		voided, err := vp.Authorizer.Void(msg.Context(), &void)
+		if err != nil && payment.IsRetryable(err) {
+			return "", nil, retryable("error when voiding", err)
+		}
+		if failure, ok := err.(*payment.Failure); ok {
+			return voidFailed(&void, failure.Code, failure.Message)
+		}
+		if err != nil {
+			return voidFailed(&void, "processing_error", err.Error())
+		}
+
+		voided.VoidId = void.ID
+		voided.ClaimId = void.ClaimID
+		voided.Reason = void.Reason
+		voided.ProcessedAt = timestamppb.Now()
+
+		// record the void straight away, so the payment cannot be captured or voided again
+		p.ApplyVoid(voided)
+		err = vp.Payments.Save(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to save payment, %s", err)
+		}
+
+		payload, err := proto.Marshal(voided)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+		}
+
+		return payment.TypeVoided, payload, nil
+	})
+}
+
+func voidFailed(void *payment.Void, code, msg string) (string, []byte, error) {
+
+	failed := &payment.VoidFailed{
+		VoidId:         void.ID,
+		ClaimId:        void.ClaimID,
+		FailureCode:    code,
+		FailureMessage: msg,
+		ProcessedAt:    timestamppb.Now(),
+	}
+
+	payload, err := proto.Marshal(failed)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal message, %s", err.Error())
+	}
+
+	return payment.TypeVoidFailed, payload, nil
+}
+
+// NewVoidPayment is a factory for the handler: VoidPayment
+func NewVoidPayment(authorizer payment.Authorizer, payments store.Store, idempotencyStore idempotency.Store) *VoidPayment {
+
+	handler := VoidPayment{
+		Authorizer:  authorizer,
+		Payments:    payments,
+		Idempotency: idempotencyStore,
+	}
+
+	return &handler
+}
@@ -0,0 +1,6 @@
+package handler
+
+type VoidRequest struct {
+	IdempotencyToken string `json:"idempotency_token"`
+	Reason           string `json:"reason"`
+}
@@ -0,0 +1,153 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var recordsBucket = []byte("idempotency_keys")
+
+// BoltStore is a Store which persists requests to a file using the embedded database bolt
+type BoltStore struct {
+	db        *bolt.DB
+	retention time.Duration
+	lease     time.Duration
+}
+
+// Begin records that a request has started, when the key has been seen before it returns the existing Record and false
+func (bs *BoltStore) Begin(key, fingerprint string) (*Record, bool, error) {
+
+	var r *Record
+	created := false
+
+	err := bs.db.Update(func(tx *bolt.Tx) error {
+
+		records := tx.Bucket(recordsBucket)
+
+		if b := records.Get([]byte(key)); b != nil {
+			existing := &Record{}
+			if err := json.Unmarshal(b, existing); err != nil {
+				return fmt.Errorf("failed to unmarshal idempotency record, %s", err.Error())
+			}
+			if !bs.expired(existing) && !existing.abandoned(bs.lease) {
+				r = existing
+				return nil
+			}
+		}
+
+		r = &Record{Key: key, Fingerprint: fingerprint, CreatedAt: time.Now()}
+		created = true
+
+		return put(records, r)
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return r, created, nil
+}
+
+// Complete stores the response to a request which has begun
+func (bs *BoltStore) Complete(key string, response []byte) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+
+		records := tx.Bucket(recordsBucket)
+
+		b := records.Get([]byte(key))
+		if b == nil {
+			return ErrNotFound
+		}
+
+		r := &Record{}
+		if err := json.Unmarshal(b, r); err != nil {
+			return fmt.Errorf("failed to unmarshal idempotency record, %s", err.Error())
+		}
+		r.Response = response
+
+		return put(records, r)
+	})
+}
+
+// Release forgets a request, so that it can be made again
+func (bs *BoltStore) Release(key string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(recordsBucket).Delete([]byte(key))
+	})
+}
+
+// Purge forgets requests older than the retention period
+func (bs *BoltStore) Purge() error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+
+		records := tx.Bucket(recordsBucket)
+
+		// deleting while iterating with a cursor skips keys, so collect the expired keys first
+		expired := make([][]byte, 0)
+		err := records.ForEach(func(k, v []byte) error {
+			r := &Record{}
+			if err := json.Unmarshal(v, r); err != nil {
+				return fmt.Errorf("failed to unmarshal idempotency record, %s", err.Error())
+			}
+			if bs.expired(r) {
+				expired = append(expired, append([]byte{}, k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			if err := records.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Close releases the database file
+func (bs *BoltStore) Close() error {
+	return bs.db.Close()
+}
+
+func (bs *BoltStore) expired(r *Record) bool {
+	return time.Since(r.CreatedAt) > bs.retention
+}
+
+func put(records *bolt.Bucket, r *Record) error {
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record, %s", err.Error())
+	}
+
+	return records.Put([]byte(r.Key), b)
+}
+
+// NewBoltStore is a factory for a BoltStore persisting to the file at path, which remembers requests for the retention period,
+// and lets requests still in progress after the lease be begun again
+func NewBoltStore(path string, retention, lease time.Duration) (*BoltStore, error) {
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database, %s", err.Error())
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(recordsBucket)
+		return err
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bolt bucket, %s", err.Error())
+	}
+
+	return &BoltStore{db: db, retention: retention, lease: lease}, nil
+}
@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+)
+
+// ErrNotFound is returned when there is no Record for a key
+var ErrNotFound = errors.New("idempotency key not found")
+
+// Record is what is remembered about a request made with an idempotency key
+type Record struct {
+	Key string `json:"key"`
+	// Fingerprint identifies the content of the request, a repeat of it with different content is a conflict
+	Fingerprint string `json:"fingerprint"`
+	// Response is the response to the request, it is empty while the request is in progress
+	Response  []byte    `json:"response,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Completed reports whether the response to the request has been stored
+func (r *Record) Completed() bool {
+	return r.Response != nil
+}
+
+// abandoned reports whether the request is still in progress after the lease, so the process handling it is taken to
+// have crashed and another can begin it again
+func (r *Record) abandoned(lease time.Duration) bool {
+	return !r.Completed() && time.Since(r.CreatedAt) > lease
+}
+
+// Store defines the behaviour required to remember requests by their idempotency key for a retention period
+type Store interface {
+	// Begin records that a request has started, when the key has been seen before it returns the existing Record and false,
+	// unless the request was still in progress once its lease ran out, when it is begun again
+	Begin(key, fingerprint string) (*Record, bool, error)
+	// Complete stores the response to a request which has begun
+	Complete(key string, response []byte) error
+	// Release forgets a request, so that it can be made again
+	Release(key string) error
+	// Purge forgets requests older than the retention period
+	Purge() error
+	Close() error
+}
+
+// Fingerprint is a digest of the content of a request
+func Fingerprint(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// PurgeEvery purges the Store at an interval until the context is done
+func PurgeEvery(ctx context.Context, s Store, interval time.Duration, logger watermill.LoggerAdapter) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Purge(); err != nil {
+				logger.Error("failed to purge idempotency keys", err, nil)
+			}
+		}
+	}
+}
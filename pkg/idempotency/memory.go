@@ -0,0 +1,92 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store which keeps requests in memory, they are forgotten when the process exits
+type MemoryStore struct {
+	lock      sync.Mutex
+	records   map[string]Record
+	retention time.Duration
+	lease     time.Duration
+}
+
+// Begin records that a request has started, when the key has been seen before it returns the existing Record and false
+func (ms *MemoryStore) Begin(key, fingerprint string) (*Record, bool, error) {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	if existing, ok := ms.records[key]; ok && !ms.expired(existing) && !existing.abandoned(ms.lease) {
+		return &existing, false, nil
+	}
+
+	r := Record{Key: key, Fingerprint: fingerprint, CreatedAt: time.Now()}
+	ms.records[key] = r
+
+	return &r, true, nil
+}
+
+// Complete stores the response to a request which has begun
+func (ms *MemoryStore) Complete(key string, response []byte) error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	r, ok := ms.records[key]
+	if !ok {
+		return ErrNotFound
+	}
+
+	r.Response = response
+	ms.records[key] = r
+
+	return nil
+}
+
+// Release forgets a request, so that it can be made again
+func (ms *MemoryStore) Release(key string) error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	delete(ms.records, key)
+
+	return nil
+}
+
+// Purge forgets requests older than the retention period
+func (ms *MemoryStore) Purge() error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	for key, r := range ms.records {
+		if ms.expired(r) {
+			delete(ms.records, key)
+		}
+	}
+
+	return nil
+}
+
+// Close does nothing, there is nothing to release
+func (ms *MemoryStore) Close() error {
+	return nil
+}
+
+func (ms *MemoryStore) expired(r Record) bool {
+	return time.Since(r.CreatedAt) > ms.retention
+}
+
+// NewMemoryStore is a factory for an empty MemoryStore which remembers requests for the retention period, and lets
+// requests still in progress after the lease be begun again
+func NewMemoryStore(retention, lease time.Duration) *MemoryStore {
+	return &MemoryStore{
+		records:   make(map[string]Record),
+		retention: retention,
+		lease:     lease,
+	}
+}
@@ -0,0 +1,78 @@
+package payment
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Card brands which can be recognised from the number of a card
+const (
+	BrandVisa       = "visa"
+	BrandMastercard = "mastercard"
+	BrandAmex       = "amex"
+	BrandDiscover   = "discover"
+	BrandJCB        = "jcb"
+	BrandDiners     = "diners"
+	BrandUnknown    = "unknown"
+)
+
+// brandRanges are the ranges of the leading digits of card numbers issued by each brand, checked in order
+var brandRanges = []struct {
+	brand  string
+	digits int
+	from   int
+	to     int
+}{
+	{BrandAmex, 2, 34, 34},
+	{BrandAmex, 2, 37, 37},
+	{BrandDiners, 3, 300, 305},
+	{BrandDiners, 2, 36, 36},
+	{BrandDiners, 2, 38, 39},
+	{BrandJCB, 4, 3528, 3589},
+	{BrandDiscover, 4, 6011, 6011},
+	{BrandDiscover, 3, 644, 649},
+	{BrandDiscover, 2, 65, 65},
+	{BrandMastercard, 2, 51, 55},
+	{BrandMastercard, 4, 2221, 2720},
+	{BrandVisa, 1, 4, 4},
+}
+
+// CardBrand is the brand which issued a card, recognised from the leading digits of its number, or BrandUnknown
+func CardBrand(number string) string {
+
+	for _, r := range brandRanges {
+		if len(number) < r.digits {
+			continue
+		}
+		prefix, err := strconv.Atoi(number[:r.digits])
+		if err != nil {
+			return BrandUnknown
+		}
+		if prefix >= r.from && prefix <= r.to {
+			return r.brand
+		}
+	}
+
+	return BrandUnknown
+}
+
+// BIN is the bank identification number of a card, the leading six digits of its number which identify its issuer
+func BIN(number string) string {
+	if len(number) < 6 {
+		return number
+	}
+	return number[:6]
+}
+
+// IsMaskedCardNumber reports whether a card number has been masked, so it can no longer be paid with
+func IsMaskedCardNumber(number string) bool {
+	return strings.Contains(number, "*")
+}
+
+// MaskCardNumber hides all but the BIN and last four digits of a card number, so it can be shown without exposing the card
+func MaskCardNumber(number string) string {
+	if len(number) <= 10 {
+		return strings.Repeat("*", len(number))
+	}
+	return number[:6] + strings.Repeat("*", len(number)-10) + number[len(number)-4:]
+}
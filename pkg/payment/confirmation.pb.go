@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: confirmation.proto
+
+package payment
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Confirm resumes a payment which required the payer to take action, such as authenticating with their bank
+type Confirm struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID              string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	ClaimID         string `protobuf:"bytes,2,opt,name=ClaimID,proto3" json:"ClaimID,omitempty"`
+	VendorReference string `protobuf:"bytes,3,opt,name=VendorReference,proto3" json:"VendorReference,omitempty"`
+	// Abandon fails the payment if the action has still not been taken
+	Abandon   bool   `protobuf:"varint,4,opt,name=Abandon,proto3" json:"Abandon,omitempty"`
+	Processor string `protobuf:"bytes,5,opt,name=Processor,proto3" json:"Processor,omitempty"`
+	// RedirectResult is what the payer was sent back with once they took the action, for processors which need it to resume the payment
+	RedirectResult string `protobuf:"bytes,6,opt,name=RedirectResult,proto3" json:"RedirectResult,omitempty"`
+	// AutoCapture takes the money once the payment is authorised, rather than holding it to be captured later
+	AutoCapture bool `protobuf:"varint,7,opt,name=AutoCapture,proto3" json:"AutoCapture,omitempty"`
+	// Amount is how much the payment is for, in minor units
+	Amount int64 `protobuf:"varint,8,opt,name=Amount,proto3" json:"Amount,omitempty"`
+}
+
+func (x *Confirm) Reset() {
+	*x = Confirm{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_confirmation_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Confirm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Confirm) ProtoMessage() {}
+
+func (x *Confirm) ProtoReflect() protoreflect.Message {
+	mi := &file_confirmation_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Confirm.ProtoReflect.Descriptor instead.
+func (*Confirm) Descriptor() ([]byte, []int) {
+	return file_confirmation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Confirm) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Confirm) GetClaimID() string {
+	if x != nil {
+		return x.ClaimID
+	}
+	return ""
+}
+
+func (x *Confirm) GetVendorReference() string {
+	if x != nil {
+		return x.VendorReference
+	}
+	return ""
+}
+
+func (x *Confirm) GetAbandon() bool {
+	if x != nil {
+		return x.Abandon
+	}
+	return false
+}
+
+func (x *Confirm) GetProcessor() string {
+	if x != nil {
+		return x.Processor
+	}
+	return ""
+}
+
+func (x *Confirm) GetRedirectResult() string {
+	if x != nil {
+		return x.RedirectResult
+	}
+	return ""
+}
+
+func (x *Confirm) GetAutoCapture() bool {
+	if x != nil {
+		return x.AutoCapture
+	}
+	return false
+}
+
+func (x *Confirm) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+var File_confirmation_proto protoreflect.FileDescriptor
+
+var file_confirmation_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xf7, 0x01,
+	0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6c, 0x61,
+	0x69, 0x6d, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6c, 0x61, 0x69,
+	0x6d, 0x49, 0x44, 0x12, 0x28, 0x0a, 0x0f, 0x56, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x52, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x56, 0x65,
+	0x6e, 0x64, 0x6f, 0x72, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x41, 0x62, 0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x41, 0x62, 0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x6f, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x52,
+	0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x20, 0x0a,
+	0x0b, 0x41, 0x75, 0x74, 0x6f, 0x43, 0x61, 0x70, 0x74, 0x75, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0b, 0x41, 0x75, 0x74, 0x6f, 0x43, 0x61, 0x70, 0x74, 0x75, 0x72, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x61, 0x6e, 0x6e, 0x69, 0x6f, 0x6e, 0x30, 0x30, 0x37,
+	0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x2d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x74,
+	0x79, 0x70, 0x65, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x3b, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_confirmation_proto_rawDescOnce sync.Once
+	file_confirmation_proto_rawDescData = file_confirmation_proto_rawDesc
+)
+
+func file_confirmation_proto_rawDescGZIP() []byte {
+	file_confirmation_proto_rawDescOnce.Do(func() {
+		file_confirmation_proto_rawDescData = protoimpl.X.CompressGZIP(file_confirmation_proto_rawDescData)
+	})
+	return file_confirmation_proto_rawDescData
+}
+
+var file_confirmation_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_confirmation_proto_goTypes = []interface{}{
+	(*Confirm)(nil), // 0: payment.Confirm
+}
+var file_confirmation_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_confirmation_proto_init() }
+func file_confirmation_proto_init() {
+	if File_confirmation_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_confirmation_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Confirm); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_confirmation_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_confirmation_proto_goTypes,
+		DependencyIndexes: file_confirmation_proto_depIdxs,
+		MessageInfos:      file_confirmation_proto_msgTypes,
+	}.Build()
+	File_confirmation_proto = out.File
+	file_confirmation_proto_rawDesc = nil
+	file_confirmation_proto_goTypes = nil
+	file_confirmation_proto_depIdxs = nil
+}
@@ -0,0 +1,97 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency which payments can be taken in.
+// Amounts are always expressed in the minor unit of the currency, e.g. pence for GBP, yen for JPY and fils for BHD
+type Currency struct {
+	Code string
+	// Exponent is the number of decimal places between the minor and major unit
+	Exponent int
+	// MinAmount is the smallest payment which can be taken, in minor units
+	MinAmount int64
+	// MaxAmount is the largest payment which can be taken, in minor units
+	MaxAmount int64
+}
+
+var currencies = map[string]Currency{
+	"AUD": {Code: "AUD", Exponent: 2, MinAmount: 50, MaxAmount: 2000000},
+	"BHD": {Code: "BHD", Exponent: 3, MinAmount: 200, MaxAmount: 5000000},
+	"CAD": {Code: "CAD", Exponent: 2, MinAmount: 50, MaxAmount: 2000000},
+	"CHF": {Code: "CHF", Exponent: 2, MinAmount: 50, MaxAmount: 1000000},
+	"CLP": {Code: "CLP", Exponent: 0, MinAmount: 500, MaxAmount: 10000000},
+	"CNY": {Code: "CNY", Exponent: 2, MinAmount: 400, MaxAmount: 10000000},
+	"CZK": {Code: "CZK", Exponent: 2, MinAmount: 1500, MaxAmount: 30000000},
+	"DKK": {Code: "DKK", Exponent: 2, MinAmount: 250, MaxAmount: 10000000},
+	"EUR": {Code: "EUR", Exponent: 2, MinAmount: 50, MaxAmount: 1000000},
+	"GBP": {Code: "GBP", Exponent: 2, MinAmount: 30, MaxAmount: 1000000},
+	"HKD": {Code: "HKD", Exponent: 2, MinAmount: 400, MaxAmount: 10000000},
+	"HUF": {Code: "HUF", Exponent: 2, MinAmount: 17500, MaxAmount: 500000000},
+	"INR": {Code: "INR", Exponent: 2, MinAmount: 5000, MaxAmount: 100000000},
+	"ISK": {Code: "ISK", Exponent: 0, MinAmount: 100, MaxAmount: 2000000},
+	"JOD": {Code: "JOD", Exponent: 3, MinAmount: 400, MaxAmount: 10000000},
+	"JPY": {Code: "JPY", Exponent: 0, MinAmount: 50, MaxAmount: 1500000},
+	"KRW": {Code: "KRW", Exponent: 0, MinAmount: 700, MaxAmount: 15000000},
+	"KWD": {Code: "KWD", Exponent: 3, MinAmount: 200, MaxAmount: 4000000},
+	"MXN": {Code: "MXN", Exponent: 2, MinAmount: 1000, MaxAmount: 25000000},
+	"NOK": {Code: "NOK", Exponent: 2, MinAmount: 300, MaxAmount: 15000000},
+	"NZD": {Code: "NZD", Exponent: 2, MinAmount: 50, MaxAmount: 2000000},
+	"OMR": {Code: "OMR", Exponent: 3, MinAmount: 200, MaxAmount: 5000000},
+	"PLN": {Code: "PLN", Exponent: 2, MinAmount: 200, MaxAmount: 5000000},
+	"SEK": {Code: "SEK", Exponent: 2, MinAmount: 300, MaxAmount: 15000000},
+	"SGD": {Code: "SGD", Exponent: 2, MinAmount: 50, MaxAmount: 2000000},
+	"TND": {Code: "TND", Exponent: 3, MinAmount: 1500, MaxAmount: 30000000},
+	"USD": {Code: "USD", Exponent: 2, MinAmount: 50, MaxAmount: 1000000},
+	"VND": {Code: "VND", Exponent: 0, MinAmount: 12000, MaxAmount: 250000000},
+	"ZAR": {Code: "ZAR", Exponent: 2, MinAmount: 800, MaxAmount: 20000000},
+}
+
+// LookupCurrency finds a supported Currency by its ISO 4217 code
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := currencies[code]
+	return c, ok
+}
+
+// Format writes an amount in minor units of the Currency as a decimal number of major units, e.g. 9999 GBP is "99.99"
+func (c Currency) Format(amount int64) string {
+
+	if c.Exponent == 0 {
+		return fmt.Sprintf("%d", amount)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%0*d", c.Exponent+1, amount)
+	point := len(digits) - c.Exponent
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Validate checks an amount in minor units can be taken in the Currency, returning an error if not
+func (c Currency) Validate(amount int64) error {
+
+	if amount < c.MinAmount {
+		return fmt.Errorf("must be at least %s %s (%d in minor units)", c.Format(c.MinAmount), c.Code, c.MinAmount)
+	}
+	if amount > c.MaxAmount {
+		return fmt.Errorf("must be no more than %s %s (%d in minor units)", c.Format(c.MaxAmount), c.Code, c.MaxAmount)
+	}
+
+	return nil
+}
+
+// ParseCurrency finds a supported Currency by its ISO 4217 code, in any case
+func ParseCurrency(code string) (Currency, error) {
+	c, ok := LookupCurrency(strings.ToUpper(code))
+	if !ok {
+		return Currency{}, fmt.Errorf("currency %s is not supported", code)
+	}
+	return c, nil
+}
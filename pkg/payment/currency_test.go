@@ -0,0 +1,115 @@
+package payment
+
+import (
+	"testing"
+)
+
+func TestCurrencyFormat(t *testing.T) {
+
+	tests := []struct {
+		code     string
+		amount   int64
+		expected string
+	}{
+		{code: "GBP", amount: 9999, expected: "99.99"},
+		{code: "GBP", amount: 30, expected: "0.30"},
+		{code: "GBP", amount: 5, expected: "0.05"},
+		{code: "GBP", amount: 0, expected: "0.00"},
+		{code: "GBP", amount: -150, expected: "-1.50"},
+		{code: "JPY", amount: 9999, expected: "9999"},
+		{code: "JPY", amount: 50, expected: "50"},
+		{code: "KWD", amount: 9999, expected: "9.999"},
+		{code: "KWD", amount: 200, expected: "0.200"},
+		{code: "KWD", amount: 7, expected: "0.007"},
+		{code: "KWD", amount: -1500, expected: "-1.500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.expected, func(t *testing.T) {
+
+			c, ok := LookupCurrency(tt.code)
+			if !ok {
+				t.Fatalf("expected %s to be supported", tt.code)
+			}
+
+			if formatted := c.Format(tt.amount); formatted != tt.expected {
+				t.Fatalf("expected %d %s to be formatted as %s, got %s", tt.amount, tt.code, tt.expected, formatted)
+			}
+		})
+	}
+}
+
+func TestCurrencyValidate(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		code   string
+		amount int64
+		err    string
+	}{
+		{name: "GBP within range", code: "GBP", amount: 9999},
+		{name: "GBP minimum", code: "GBP", amount: 30},
+		{name: "GBP maximum", code: "GBP", amount: 1000000},
+		{name: "GBP below minimum", code: "GBP", amount: 29, err: "must be at least 0.30 GBP (30 in minor units)"},
+		{name: "GBP above maximum", code: "GBP", amount: 1000001, err: "must be no more than 10000.00 GBP (1000000 in minor units)"},
+		{name: "JPY within range", code: "JPY", amount: 9999},
+		{name: "JPY below minimum", code: "JPY", amount: 49, err: "must be at least 50 JPY (50 in minor units)"},
+		{name: "JPY above maximum", code: "JPY", amount: 1500001, err: "must be no more than 1500000 JPY (1500000 in minor units)"},
+		{name: "KWD within range", code: "KWD", amount: 9999},
+		{name: "KWD below minimum", code: "KWD", amount: 199, err: "must be at least 0.200 KWD (200 in minor units)"},
+		{name: "KWD above maximum", code: "KWD", amount: 4000001, err: "must be no more than 4000.000 KWD (4000000 in minor units)"},
+		{name: "negative", code: "GBP", amount: -100, err: "must be at least 0.30 GBP (30 in minor units)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			c, ok := LookupCurrency(tt.code)
+			if !ok {
+				t.Fatalf("expected %s to be supported", tt.code)
+			}
+
+			err := c.Validate(tt.amount)
+			if tt.err == "" && err != nil {
+				t.Fatalf("expected %d %s to be valid, got %s", tt.amount, tt.code, err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Fatalf("expected %d %s to be invalid with %q, got %v", tt.amount, tt.code, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+
+	tests := []struct {
+		code      string
+		expected  string
+		supported bool
+	}{
+		{code: "GBP", expected: "GBP", supported: true},
+		{code: "gbp", expected: "GBP", supported: true},
+		{code: "Jpy", expected: "JPY", supported: true},
+		{code: "XXX"},
+		{code: "BTC"},
+		{code: "GB"},
+		{code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+
+			c, err := ParseCurrency(tt.code)
+			if tt.supported && (err != nil || c.Code != tt.expected) {
+				t.Fatalf("expected %q to be parsed as %s, got %+v and %v", tt.code, tt.expected, c, err)
+			}
+			if !tt.supported && err == nil {
+				t.Fatalf("expected %q not to be supported, got %+v", tt.code, c)
+			}
+
+			if _, ok := LookupCurrency(tt.code); ok != (tt.supported && tt.code == tt.expected) {
+				t.Fatalf("expected looking up %q to find it %t, got %t", tt.code, tt.supported && tt.code == tt.expected, ok)
+			}
+		})
+	}
+}
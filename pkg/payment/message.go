@@ -0,0 +1,103 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// MessageTypeKey is the metadata key naming the type of protobuf message carried in the payload of a message
+const MessageTypeKey = "type"
+
+// The types of message carried on the bus
+const (
+	TypeClaim           = "Claim"
+	TypeRefund          = "Refund"
+	TypeOutcome         = "Outcome"
+	TypeRefundSucceeded = "RefundSucceeded"
+	TypeRefundFailed    = "RefundFailed"
+	TypeAuthorize       = "Authorize"
+	TypeCapture         = "Capture"
+	TypeVoid            = "Void"
+	TypeCaptured        = "Captured"
+	TypeCaptureFailed   = "CaptureFailed"
+	TypeVoided          = "Voided"
+	TypeVoidFailed      = "VoidFailed"
+	TypeConfirm         = "Confirm"
+
+	TypeBreakerStateChanged = "BreakerStateChanged"
+)
+
+// messages creates an empty protobuf message of each type carried on the bus
+var messages = map[string]func() proto.Message{
+	TypeClaim:           func() proto.Message { return &Claim{} },
+	TypeRefund:          func() proto.Message { return &Refund{} },
+	TypeOutcome:         func() proto.Message { return &Outcome{} },
+	TypeRefundSucceeded: func() proto.Message { return &RefundSucceeded{} },
+	TypeRefundFailed:    func() proto.Message { return &RefundFailed{} },
+	TypeAuthorize:       func() proto.Message { return &Authorize{} },
+	TypeCapture:         func() proto.Message { return &Capture{} },
+	TypeVoid:            func() proto.Message { return &Void{} },
+	TypeCaptured:        func() proto.Message { return &Captured{} },
+	TypeCaptureFailed:   func() proto.Message { return &CaptureFailed{} },
+	TypeVoided:          func() proto.Message { return &Voided{} },
+	TypeVoidFailed:      func() proto.Message { return &VoidFailed{} },
+	TypeConfirm:         func() proto.Message { return &Confirm{} },
+
+	TypeBreakerStateChanged: func() proto.Message { return &BreakerStateChanged{} },
+}
+
+// Unmarshal decodes the payload of a message of the given type, returning an error if the type is unknown
+func Unmarshal(messageType string, payload []byte) (proto.Message, error) {
+
+	m, ok := messages[messageType]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type %s", messageType)
+	}
+
+	decoded := m()
+	if err := proto.Unmarshal(payload, decoded); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s, %s", messageType, err.Error())
+	}
+
+	return decoded, nil
+}
+
+// ClaimID is the ID of the Claim which a message carried on the bus is about, which identifies its payment
+func ClaimID(m proto.Message) string {
+	switch m := m.(type) {
+	case *Claim:
+		return m.ID
+	case *Authorize:
+		return m.GetClaim().GetID()
+	case interface{ GetClaimID() string }:
+		return m.GetClaimID()
+	case interface{ GetClaimId() string }:
+		return m.GetClaimId()
+	}
+	return ""
+}
+
+// ClaimOf is the Claim carried by a message which takes a payment, or nil for any other message
+func ClaimOf(m proto.Message) *Claim {
+	switch m := m.(type) {
+	case *Claim:
+		return m
+	case *Authorize:
+		return m.GetClaim()
+	}
+	return nil
+}
+
+// MaskCard hides the number of the card a message would take a payment from, reporting whether it carried one
+func MaskCard(m proto.Message) bool {
+
+	claim := ClaimOf(m)
+	if claim.GetPayer() == nil {
+		return false
+	}
+
+	claim.Payer.Number = MaskCardNumber(claim.Payer.Number)
+
+	return true
+}
@@ -0,0 +1,274 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Payment is the lifecycle of a payment, from its Claim being received until the money is taken, released or given back.
+// It moves between states as commands are carried out and events happen to it, following the transitions table,
+// and rejects anything its state does not allow with an IllegalTransitionError
+type Payment struct {
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	VendorReference string `json:"vendor_reference"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+	AmountCaptured  int64  `json:"amount_captured"`
+	AmountRefunded  int64  `json:"amount_refunded"`
+	Processor       string `json:"processor,omitempty"`
+	// AutoCapture is whether the money is taken as soon as the Payment is authorised, rather than held to be captured later
+	AutoCapture bool `json:"auto_capture,omitempty"`
+}
+
+// transitions is the table of the commands and events each state allows, keyed by their message type,
+// with the states a Payment can move to when they happen. Anything not in the table for a state is illegal
+var transitions = map[string]map[string][]string{
+	StatusReceived: {
+		TypeClaim:     {StatusPending},
+		TypeAuthorize: {StatusPending},
+	},
+	// a Claim which could not be processed is given to the processor again when it is retried
+	StatusPending: {
+		TypeClaim:     {StatusPending},
+		TypeAuthorize: {StatusPending},
+		TypeOutcome:   {StatusCaptured, StatusAuthorised, StatusFailed, StatusRequiresAction},
+	},
+	// the payer may not have taken the action yet when the payment is confirmed
+	StatusRequiresAction: {
+		TypeConfirm: {StatusRequiresAction},
+		TypeOutcome: {StatusCaptured, StatusAuthorised, StatusFailed, StatusRequiresAction},
+	},
+	StatusAuthorised: {
+		TypeCapture:  {StatusAuthorised},
+		TypeVoid:     {StatusAuthorised},
+		TypeCaptured: {StatusCaptured},
+		TypeVoided:   {StatusVoided},
+	},
+	StatusCaptured: {
+		TypeRefund:          {StatusCaptured},
+		TypeRefundSucceeded: {StatusPartiallyRefunded, StatusRefunded},
+	},
+	StatusPartiallyRefunded: {
+		TypeRefund:          {StatusPartiallyRefunded},
+		TypeRefundSucceeded: {StatusPartiallyRefunded, StatusRefunded},
+	},
+}
+
+// IllegalTransitionError is returned when a Payment is sent a command, or an event happens to it, which its state does not allow
+type IllegalTransitionError struct {
+	ID     string
+	Status string
+	Type   string
+	To     string
+}
+
+func (ite *IllegalTransitionError) Error() string {
+	if ite.To == "" {
+		return fmt.Sprintf("payment %s is %s so does not allow %s", ite.ID, ite.Status, ite.Type)
+	}
+	return fmt.Sprintf("payment %s is %s so cannot become %s by %s", ite.ID, ite.Status, ite.To, ite.Type)
+}
+
+// NewPayment starts the lifecycle of the payment for a Claim, which has been received but not yet processed
+func NewPayment(c *Claim) *Payment {
+	return &Payment{
+		ID:       c.ID,
+		Status:   StatusReceived,
+		Amount:   c.Amount.GetValue(),
+		Currency: c.Amount.GetCurrency(),
+	}
+}
+
+// Allows reports whether the state of the Payment allows a command or event of the given message type, returning an error if not
+func (p *Payment) Allows(messageType string) error {
+	if _, ok := transitions[p.Status][messageType]; !ok {
+		return &IllegalTransitionError{ID: p.ID, Status: p.Status, Type: messageType}
+	}
+	return nil
+}
+
+// Commands lists the commands the state of the Payment allows, in lower case
+func (p *Payment) Commands() []string {
+
+	var commands []string
+	for _, t := range []string{TypeClaim, TypeAuthorize, TypeConfirm, TypeCapture, TypeVoid, TypeRefund} {
+		if p.Allows(t) == nil {
+			commands = append(commands, strings.ToLower(t))
+		}
+	}
+
+	return commands
+}
+
+// transition moves the Payment to a state by a command or event of the given message type, returning an error if the table does not allow it
+func (p *Payment) transition(messageType, to string) error {
+
+	for _, allowed := range transitions[p.Status][messageType] {
+		if allowed == to {
+			p.Status = to
+			return nil
+		}
+	}
+
+	return &IllegalTransitionError{ID: p.ID, Status: p.Status, Type: messageType, To: to}
+}
+
+// Process moves the Payment to pending as its Claim is given to a processor, by a command of type TypeClaim or TypeAuthorize,
+// only the first of which takes the money once it is authorised
+func (p *Payment) Process(messageType string) error {
+
+	if err := p.transition(messageType, StatusPending); err != nil {
+		return err
+	}
+	p.AutoCapture = messageType == TypeClaim
+
+	return nil
+}
+
+// ApplyOutcome moves the Payment to the state reached by the Outcome of processing its Claim
+func (p *Payment) ApplyOutcome(o *Outcome) error {
+
+	if err := p.transition(TypeOutcome, OutcomeStatus(o)); err != nil {
+		return err
+	}
+
+	p.VendorReference = o.VendorReference
+	if o.Amount != 0 {
+		p.Amount = o.Amount
+	}
+	if o.Currency != "" {
+		p.Currency = o.Currency
+	}
+	p.AmountCaptured = o.AmountCaptured
+	if o.Processor != "" {
+		p.Processor = o.Processor
+	}
+
+	return nil
+}
+
+// Confirm checks the Payment allows a Confirm, filling in what it resumes
+func (p *Payment) Confirm(c *Confirm) error {
+
+	if err := p.Allows(TypeConfirm); err != nil {
+		return err
+	}
+
+	c.VendorReference = p.VendorReference
+	c.Processor = p.Processor
+	c.AutoCapture = p.AutoCapture
+	c.Amount = p.Amount
+
+	return nil
+}
+
+// CapturableAmount is how much of the Payment, in minor units, is held by an authorisation and can still be captured
+func (p *Payment) CapturableAmount() int64 {
+	if p.Allows(TypeCapture) != nil {
+		return 0
+	}
+	return p.Amount
+}
+
+// RefundableAmount is how much of the Payment, in minor units, can still be refunded
+func (p *Payment) RefundableAmount() int64 {
+	if p.Allows(TypeRefund) != nil {
+		return 0
+	}
+	return p.AmountCaptured - p.AmountRefunded
+}
+
+// Capture checks the Payment allows a Capture, filling in what it captures and in which currency, returning a Failure if it asks for too much
+func (p *Payment) Capture(c *Capture) error {
+
+	if err := p.Allows(TypeCapture); err != nil {
+		return err
+	}
+
+	capturable := p.CapturableAmount()
+	if c.Amount == 0 {
+		c.Amount = capturable
+	}
+	if c.Amount > capturable {
+		return &Failure{Code: "amount_too_large", Message: fmt.Sprintf("only %d can be captured", capturable)}
+	}
+
+	c.VendorReference = p.VendorReference
+	c.Currency = p.Currency
+	c.Processor = p.Processor
+
+	return nil
+}
+
+// ApplyCaptured moves the Payment to captured once an amount of what it authorised has been taken
+func (p *Payment) ApplyCaptured(amount int64) error {
+
+	if err := p.transition(TypeCaptured, StatusCaptured); err != nil {
+		return err
+	}
+
+	p.AmountCaptured = amount
+
+	return nil
+}
+
+// Void checks the Payment allows a Void, filling in what it releases
+func (p *Payment) Void(v *Void) error {
+
+	if err := p.Allows(TypeVoid); err != nil {
+		return err
+	}
+
+	v.VendorReference = p.VendorReference
+	v.Processor = p.Processor
+
+	return nil
+}
+
+// ApplyVoided moves the Payment to voided once the money it authorised has been released
+func (p *Payment) ApplyVoided() error {
+	return p.transition(TypeVoided, StatusVoided)
+}
+
+// Refund checks the Payment allows a Refund, filling in what it gives back, returning a Failure if it asks for too much
+func (p *Payment) Refund(r *Refund) error {
+
+	if err := p.Allows(TypeRefund); err != nil {
+		return err
+	}
+
+	refundable := p.RefundableAmount()
+	if r.Amount == 0 {
+		r.Amount = refundable
+	}
+	if refundable <= 0 {
+		return &Failure{Code: "payment_not_refundable", Message: "there is nothing left to refund"}
+	}
+	if r.Amount > refundable {
+		return &Failure{Code: "amount_too_large", Message: fmt.Sprintf("only %d can be refunded", refundable)}
+	}
+
+	r.VendorReference = p.VendorReference
+	r.Currency = p.Currency
+	r.Processor = p.Processor
+
+	return nil
+}
+
+// ApplyRefunded moves the Payment to partially refunded, or refunded once everything captured has been given back
+func (p *Payment) ApplyRefunded(amount int64) error {
+
+	to := StatusPartiallyRefunded
+	if p.AmountRefunded+amount >= p.AmountCaptured {
+		to = StatusRefunded
+	}
+
+	if err := p.transition(TypeRefundSucceeded, to); err != nil {
+		return err
+	}
+
+	p.AmountRefunded += amount
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+)
+
+var statuses = []string{
+	StatusReceived,
+	StatusPending,
+	StatusRequiresAction,
+	StatusAuthorised,
+	StatusCaptured,
+	StatusPartiallyRefunded,
+	StatusRefunded,
+	StatusFailed,
+	StatusVoided,
+}
+
+var messageTypes = []string{
+	TypeClaim,
+	TypeAuthorize,
+	TypeConfirm,
+	TypeOutcome,
+	TypeCapture,
+	TypeCaptured,
+	TypeCaptureFailed,
+	TypeVoid,
+	TypeVoided,
+	TypeVoidFailed,
+	TypeRefund,
+	TypeRefundSucceeded,
+	TypeRefundFailed,
+}
+
+func TestAllows(t *testing.T) {
+
+	allowed := map[string][]string{
+		StatusReceived:          {TypeClaim, TypeAuthorize},
+		StatusPending:           {TypeClaim, TypeAuthorize, TypeOutcome},
+		StatusRequiresAction:    {TypeConfirm, TypeOutcome},
+		StatusAuthorised:        {TypeCapture, TypeVoid, TypeCaptured, TypeVoided},
+		StatusCaptured:          {TypeRefund, TypeRefundSucceeded},
+		StatusPartiallyRefunded: {TypeRefund, TypeRefundSucceeded},
+	}
+
+	for _, status := range statuses {
+		for _, messageType := range messageTypes {
+			want := false
+			for _, a := range allowed[status] {
+				want = want || a == messageType
+			}
+
+			t.Run(status+"/"+messageType, func(t *testing.T) {
+				p := &Payment{ID: "payment", Status: status}
+				err := p.Allows(messageType)
+				if want && err != nil {
+					t.Fatalf("expected %s to allow %s, got %s", status, messageType, err)
+				}
+				if !want {
+					var ite *IllegalTransitionError
+					if !errors.As(err, &ite) {
+						t.Fatalf("expected %s not to allow %s, got %v", status, messageType, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestTransitions(t *testing.T) {
+
+	apply := map[string]func(p *Payment) error{
+		"process claim":           func(p *Payment) error { return p.Process(TypeClaim) },
+		"process authorize":       func(p *Payment) error { return p.Process(TypeAuthorize) },
+		"captured outcome":        func(p *Payment) error { return p.ApplyOutcome(&Outcome{Status: Outcome_SUCCEEDED}) },
+		"authorised outcome":      func(p *Payment) error { return p.ApplyOutcome(&Outcome{Status: Outcome_AUTHORISED}) },
+		"declined outcome":        func(p *Payment) error { return p.ApplyOutcome(&Outcome{Status: Outcome_DECLINED}) },
+		"requires action outcome": func(p *Payment) error { return p.ApplyOutcome(&Outcome{Status: Outcome_REQUIRES_ACTION}) },
+		"confirm":                 func(p *Payment) error { return p.Confirm(&Confirm{}) },
+		"capture":                 func(p *Payment) error { return p.Capture(&Capture{}) },
+		"captured":                func(p *Payment) error { return p.ApplyCaptured(1000) },
+		"void":                    func(p *Payment) error { return p.Void(&Void{}) },
+		"voided":                  func(p *Payment) error { return p.ApplyVoided() },
+		"refund":                  func(p *Payment) error { return p.Refund(&Refund{Amount: 100}) },
+		"refunded in part":        func(p *Payment) error { return p.ApplyRefunded(100) },
+		"refunded in full":        func(p *Payment) error { return p.ApplyRefunded(900) },
+	}
+
+	// the state each status moves to, keyed by what is applied, anything missing is illegal and leaves the status as it was
+	moves := map[string]map[string]string{
+		StatusReceived: {
+			"process claim":     StatusPending,
+			"process authorize": StatusPending,
+		},
+		StatusPending: {
+			"process claim":           StatusPending,
+			"process authorize":       StatusPending,
+			"captured outcome":        StatusCaptured,
+			"authorised outcome":      StatusAuthorised,
+			"declined outcome":        StatusFailed,
+			"requires action outcome": StatusRequiresAction,
+		},
+		StatusRequiresAction: {
+			"confirm":                 StatusRequiresAction,
+			"captured outcome":        StatusCaptured,
+			"authorised outcome":      StatusAuthorised,
+			"declined outcome":        StatusFailed,
+			"requires action outcome": StatusRequiresAction,
+		},
+		StatusAuthorised: {
+			"capture":  StatusAuthorised,
+			"captured": StatusCaptured,
+			"void":     StatusAuthorised,
+			"voided":   StatusVoided,
+		},
+		StatusCaptured: {
+			"refund":           StatusCaptured,
+			"refunded in part": StatusPartiallyRefunded,
+			"refunded in full": StatusRefunded,
+		},
+		StatusPartiallyRefunded: {
+			"refund":           StatusPartiallyRefunded,
+			"refunded in part": StatusPartiallyRefunded,
+			"refunded in full": StatusRefunded,
+		},
+	}
+
+	for _, status := range statuses {
+		for name, change := range apply {
+			to, legal := moves[status][name]
+			if !legal {
+				to = status
+			}
+
+			t.Run(status+"/"+name, func(t *testing.T) {
+				p := &Payment{ID: "payment", Status: status, Amount: 1000, AmountCaptured: 1000, AmountRefunded: 100}
+				err := change(p)
+				if legal && err != nil {
+					t.Fatalf("expected %s to be allowed from %s, got %s", name, status, err)
+				}
+				if !legal {
+					var ite *IllegalTransitionError
+					if !errors.As(err, &ite) {
+						t.Fatalf("expected %s not to be allowed from %s, got %v", name, status, err)
+					}
+				}
+				if p.Status != to {
+					t.Fatalf("expected %s from %s to move the payment to %s, it is %s", name, status, to, p.Status)
+				}
+			})
+		}
+	}
+}
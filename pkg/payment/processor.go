@@ -1,6 +1,127 @@
 package payment
 
-// Processor defines the behaviour required of a Payment Service Provider
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Processor defines the behaviour required of a Payment Service Provider.
+// Claims which are declined result in an unsuccessful Outcome, an error is only returned when
+// the Claim could not be processed, and it is a RetryableError if trying again may succeed.
+// Work is abandoned when the context is done, which results in a RetryableError
 type Processor interface {
-	Process(*Claim) (*Outcome, error)
+	Process(context.Context, *Claim) (*Outcome, error)
+}
+
+// Refunder defines the behaviour required of a Payment Service Provider which can give money back.
+// A Refund which is refused results in a Failure, and one which may succeed if tried again in a RetryableError
+type Refunder interface {
+	Refund(context.Context, *Refund) (*RefundSucceeded, error)
+}
+
+// Authorizer defines the behaviour required of a Payment Service Provider which can hold money on a card to be taken later.
+// Claims which are authorised result in an Outcome with the AUTHORISED status, and the money held can then be captured or
+// released by a Void. A Capture or Void which is refused results in a Failure, and one which may succeed if tried again in a RetryableError
+type Authorizer interface {
+	Authorize(context.Context, *Claim) (*Outcome, error)
+	Capture(context.Context, *Capture) (*Captured, error)
+	Void(context.Context, *Void) (*Voided, error)
+}
+
+// Confirmer defines the behaviour required of a Payment Service Provider which can ask the payer to take action, such as
+// authenticating with their bank, before a Claim is processed. Confirm results in the Outcome of the Claim once the action
+// has been taken, which still has the REQUIRES_ACTION status if it has not, and Abandon gives up on the action being taken
+type Confirmer interface {
+	Confirm(context.Context, *Confirm) (*Outcome, error)
+	Abandon(context.Context, *Confirm) error
+}
+
+// RetryableError is returned by a Processor when a Claim could not be processed, but may be if it is tried again
+type RetryableError struct {
+	Err error
+}
+
+func (re *RetryableError) Error() string {
+	return fmt.Sprintf("retryable error, %s", re.Err.Error())
+}
+
+func (re *RetryableError) Unwrap() error {
+	return re.Err
+}
+
+// Retryable wraps an error to show that the work which caused it may succeed if tried again
+func Retryable(err error) error {
+	return &RetryableError{Err: err}
+}
+
+// IsRetryable reports whether the work which caused an error may succeed if tried again
+func IsRetryable(err error) bool {
+	_, ok := err.(*RetryableError)
+	return ok
+}
+
+// UnsentError is returned by a Processor when the request for the work never reached the Payment Service Provider,
+// so nothing was done and it is safe to give the work to another
+type UnsentError struct {
+	Err error
+}
+
+func (ue *UnsentError) Error() string {
+	return fmt.Sprintf("unsent, %s", ue.Err.Error())
+}
+
+func (ue *UnsentError) Unwrap() error {
+	return ue.Err
+}
+
+// Unsent wraps an error to show that the request for the work which caused it never reached the Payment Service Provider
+func Unsent(err error) error {
+	return &UnsentError{Err: err}
+}
+
+// IsUnsent reports whether the request for the work which caused an error, or any error it wraps, never reached the Payment Service Provider
+func IsUnsent(err error) bool {
+	var ue *UnsentError
+	return errors.As(err, &ue)
+}
+
+// AttemptedError is returned by a Processor which gave a Claim to one or more Payment Service Providers without it being
+// processed, with the Attempts it made and the name of the one the Claim last reached, if any
+type AttemptedError struct {
+	Err      error
+	Attempts []*Outcome_Attempt
+	Reached  string
+}
+
+func (ae *AttemptedError) Error() string {
+	return ae.Err.Error()
+}
+
+func (ae *AttemptedError) Unwrap() error {
+	return ae.Err
+}
+
+type reachedKey struct{}
+
+// WithReached is a context for processing a Claim which has already reached the named Payment Service Provider,
+// and may have been processed by it, so must not be given to any other
+func WithReached(ctx context.Context, processor string) context.Context {
+	return context.WithValue(ctx, reachedKey{}, processor)
+}
+
+// Reached is the name of the Payment Service Provider a Claim processed with the context has already reached, or empty if none
+func Reached(ctx context.Context) string {
+	processor, _ := ctx.Value(reachedKey{}).(string)
+	return processor
+}
+
+// Failure is returned when work was refused by a Payment Service Provider, and will be refused again if tried
+type Failure struct {
+	Code    string
+	Message string
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%s, %s", f.Code, f.Message)
 }
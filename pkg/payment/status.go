@@ -0,0 +1,40 @@
+package payment
+
+// The states a Payment moves through in its lifecycle, which are reported as its status
+const (
+	StatusReceived          = "received"
+	StatusPending           = "pending"
+	StatusAuthorised        = "authorised"
+	StatusCaptured          = "captured"
+	StatusPartiallyRefunded = "partially_refunded"
+	StatusRefunded          = "refunded"
+	StatusFailed            = "failed"
+	StatusVoided            = "voided"
+	StatusRequiresAction    = "requires_action"
+)
+
+// The statuses a refund of a Payment can be reported as having
+const (
+	RefundStatusSucceeded = "succeeded"
+	RefundStatusFailed    = "failed"
+)
+
+// OutcomeStatus is the status of a payment which has reached the given Outcome
+func OutcomeStatus(o *Outcome) string {
+	switch o.Status {
+	case Outcome_SUCCEEDED:
+		return StatusCaptured
+	case Outcome_DECLINED, Outcome_ERROR:
+		return StatusFailed
+	case Outcome_REQUIRES_ACTION:
+		return StatusRequiresAction
+	case Outcome_AUTHORISED:
+		return StatusAuthorised
+	}
+
+	// outcomes from before the status was recorded only say whether they succeeded
+	if o.Success {
+		return StatusCaptured
+	}
+	return StatusFailed
+}
@@ -0,0 +1,483 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+const (
+	processorNameAdyen = "adyen"
+	// adyenAPIVersion is the version of the Checkout api which requests are made to
+	adyenAPIVersion = "v68"
+	// adyenTestURL is where the Checkout api of the adyen test environment is
+	adyenTestURL = "https://checkout-test.adyen.com"
+)
+
+// adyenDeclineCodes translates the refusalReasonCode of a refused payment to the decline code stripe uses for the same reason,
+// so that declines are described the same whichever processor took the payment. Other reasons are a generic_decline
+var adyenDeclineCodes = map[string]string{
+	"6":  "expired_card",
+	"7":  "invalid_amount",
+	"8":  "incorrect_number",
+	"12": "insufficient_funds",
+	"14": "fraudulent",
+	"20": "fraudulent",
+	"24": "incorrect_cvc",
+	"27": "do_not_honor",
+}
+
+// AdyenProcessor is a Processor which talks to the adyen Checkout api over http with json
+type AdyenProcessor struct {
+	client          *http.Client
+	baseURL         string
+	apiKey          string
+	merchantAccount string
+	returnURL       string
+}
+
+// AdyenOptions configures how an AdyenProcessor talks to adyen
+type AdyenOptions struct {
+	APIKey string
+	// MerchantAccount is the account payments are taken for
+	MerchantAccount string
+	// BaseURL is where the Checkout api is, which is the adyen test environment when left empty
+	BaseURL string
+	// HTTPClient makes the requests to adyen, one with the Timeout is used when it is nil
+	HTTPClient *http.Client
+	// Timeout is how long a request to adyen can take before it is abandoned
+	Timeout time.Duration
+	// ReturnURL is where the payer is sent back to once they have taken an action, with {id} replaced by the id of the payment
+	ReturnURL string
+}
+
+type adyenAmount struct {
+	Currency string `json:"currency"`
+	Value    int64  `json:"value"`
+}
+
+type adyenCard struct {
+	Type        string `json:"type"`
+	Number      string `json:"number"`
+	ExpiryMonth string `json:"expiryMonth"`
+	ExpiryYear  string `json:"expiryYear"`
+}
+
+type adyenPaymentRequest struct {
+	Amount          adyenAmount       `json:"amount"`
+	Reference       string            `json:"reference"`
+	MerchantAccount string            `json:"merchantAccount"`
+	PaymentMethod   adyenCard         `json:"paymentMethod"`
+	ReturnURL       string            `json:"returnUrl,omitempty"`
+	AdditionalData  map[string]string `json:"additionalData,omitempty"`
+}
+
+type adyenAction struct {
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Method string `json:"method"`
+}
+
+type adyenDetails struct {
+	RedirectResult string `json:"redirectResult"`
+}
+
+type adyenDetailsRequest struct {
+	Details adyenDetails `json:"details"`
+}
+
+type adyenPaymentResponse struct {
+	PSPReference      string            `json:"pspReference"`
+	ResultCode        string            `json:"resultCode"`
+	RefusalReason     string            `json:"refusalReason"`
+	RefusalReasonCode string            `json:"refusalReasonCode"`
+	Action            *adyenAction      `json:"action"`
+	AdditionalData    map[string]string `json:"additionalData"`
+}
+
+type adyenModificationRequest struct {
+	MerchantAccount      string       `json:"merchantAccount"`
+	Amount               *adyenAmount `json:"amount,omitempty"`
+	Reference            string       `json:"reference"`
+	MerchantRefundReason string       `json:"merchantRefundReason,omitempty"`
+}
+
+type adyenModificationResponse struct {
+	PSPReference        string `json:"pspReference"`
+	PaymentPSPReference string `json:"paymentPspReference"`
+	Status              string `json:"status"`
+}
+
+// adyenError is the body of a response from adyen to a request it did not carry out
+type adyenError struct {
+	Status    int    `json:"status"`
+	ErrorCode string `json:"errorCode"`
+	Message   string `json:"message"`
+	ErrorType string `json:"errorType"`
+}
+
+func (ae *adyenError) Error() string {
+	return fmt.Sprintf("adyen responded %d, %s %s", ae.Status, ae.ErrorCode, ae.Message)
+}
+
+// retryable reports whether the request may succeed if it is made again, which is the case for rate limiting and errors within adyen
+func (ae *adyenError) retryable() bool {
+	return ae.Status == http.StatusTooManyRequests || ae.Status >= http.StatusInternalServerError
+}
+
+// Process will talk to adyen over http to take the payment for the Claim, returning an error, if any
+func (ap AdyenProcessor) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return ap.pay(ctx, c, true)
+}
+
+// Authorize will talk to adyen over http to hold the money for the Claim on the card without taking it, returning an error, if any
+func (ap AdyenProcessor) Authorize(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return ap.pay(ctx, c, false)
+}
+
+// pay makes a payment for the Claim, which is only captured straight away when asked to be.
+// The request made to adyen is abandoned when the context is done
+func (ap AdyenProcessor) pay(ctx context.Context, c *payment.Claim, capture bool) (*payment.Outcome, error) {
+
+	currency, err := payment.ParseCurrency(c.Amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	req := adyenPaymentRequest{
+		Amount:          adyenAmount{Currency: currency.Code, Value: c.Amount.Value},
+		Reference:       c.ID,
+		MerchantAccount: ap.merchantAccount,
+		PaymentMethod: adyenCard{
+			Type:        "scheme",
+			Number:      c.Payer.Number,
+			ExpiryMonth: c.Payer.ExpiresAt.Month,
+			ExpiryYear:  c.Payer.ExpiresAt.Year,
+		},
+	}
+	if ap.returnURL != "" {
+		req.ReturnURL = strings.Replace(ap.returnURL, "{id}", c.ID, -1)
+	}
+
+	key := idempotencyKey(c, "payment")
+	if !capture {
+		req.AdditionalData = map[string]string{"manualCapture": "true"}
+		key = idempotencyKey(c, "authorisation")
+	}
+
+	var resp adyenPaymentResponse
+	sentCtx, sent := trackSend(ctx)
+	err = ap.post(sentCtx, "/payments", key, req, &resp)
+
+	if err != nil {
+		return adyenFailure("failed to make payment", err, sent)
+	}
+
+	return adyenOutcome(&resp, c.Amount.Value, c.Amount.Currency, capture), nil
+}
+
+// adyenOutcome describes the Outcome of a payment made with adyen from its resultCode
+func adyenOutcome(resp *adyenPaymentResponse, amount int64, currency string, capture bool) *payment.Outcome {
+
+	outcome := payment.Outcome{
+		VendorReference: resp.PSPReference,
+		Amount:          amount,
+		Currency:        strings.ToUpper(currency),
+		Processor:       processorNameAdyen,
+	}
+
+	switch resp.ResultCode {
+	case "Authorised":
+		outcome.Success = true
+		outcome.Status = payment.Outcome_AUTHORISED
+		if capture {
+			outcome.Status = payment.Outcome_SUCCEEDED
+			outcome.AmountCaptured = amount
+		}
+	case "Refused":
+		outcome.Status = payment.Outcome_DECLINED
+		outcome.FailureCode = "card_declined"
+		outcome.DeclineCode = adyenDeclineCode(resp.RefusalReasonCode)
+		outcome.FailureMessage = resp.RefusalReason
+	case "RedirectShopper", "IdentifyShopper", "ChallengeShopper":
+		outcome.Status = payment.Outcome_REQUIRES_ACTION
+		if resp.Action != nil {
+			outcome.NextAction = resp.Action.Type
+			outcome.RedirectUrl = resp.Action.URL
+		}
+	default:
+		outcome.Status = payment.Outcome_ERROR
+		outcome.FailureCode = "processing_error"
+		outcome.FailureMessage = fmt.Sprintf("payment has unexpected result %s %s", resp.ResultCode, resp.RefusalReason)
+	}
+
+	if brand, ok := resp.AdditionalData["paymentMethod"]; ok {
+		outcome.Card = &payment.Outcome_Card{
+			Brand:   brand,
+			Last4:   resp.AdditionalData["cardSummary"],
+			Funding: strings.ToLower(resp.AdditionalData["fundingSource"]),
+		}
+	}
+
+	if level, ok := resp.AdditionalData["fraudResultType"]; ok {
+		outcome.Risk = &payment.Outcome_Risk{Level: strings.ToLower(level)}
+	}
+
+	return &outcome
+}
+
+// adyenDeclineCode is the decline code for the refusalReasonCode of a refused payment
+func adyenDeclineCode(refusalReasonCode string) string {
+	if code, ok := adyenDeclineCodes[refusalReasonCode]; ok {
+		return code
+	}
+	return "generic_decline"
+}
+
+// adyenFailure classifies an error from adyen. Invalid requests will never succeed so are an unsuccessful Outcome,
+// whereas network errors, rate limiting and errors within adyen are returned as a RetryableError, which is an UnsentError
+// too when the request never reached adyen
+func adyenFailure(action string, err error, sent *sendTracker) (*payment.Outcome, error) {
+
+	adyenErr, ok := err.(*adyenError)
+	if !ok || adyenErr.retryable() {
+		return nil, retry(action, err, sent)
+	}
+
+	outcome := payment.Outcome{
+		Success:        false,
+		Status:         payment.Outcome_ERROR,
+		FailureCode:    adyenErr.ErrorType,
+		FailureMessage: fmt.Sprintf("%s %s", adyenErr.ErrorCode, adyenErr.Message),
+		Processor:      processorNameAdyen,
+	}
+
+	if outcome.FailureCode == "" {
+		outcome.FailureCode = "processing_error"
+	}
+
+	return &outcome, nil
+}
+
+// Capture will talk to adyen over http to take money held by a payment, returning an error, if any.
+// Adyen accepts the capture straight away and makes it later, so the capture is taken to have been made once accepted
+func (ap AdyenProcessor) Capture(ctx context.Context, c *payment.Capture) (*payment.Captured, error) {
+
+	req := adyenModificationRequest{
+		MerchantAccount: ap.merchantAccount,
+		Amount:          &adyenAmount{Currency: c.Currency, Value: c.Amount},
+		Reference:       c.ID,
+	}
+
+	var resp adyenModificationResponse
+	err := ap.post(ctx, fmt.Sprintf("/payments/%s/captures", c.VendorReference), fmt.Sprintf("%s-capture", c.ID), req, &resp)
+
+	if err != nil {
+		return nil, adyenModificationError("failed to capture payment", err)
+	}
+
+	captured := &payment.Captured{
+		VendorReference: resp.PSPReference,
+		Amount:          c.Amount,
+		Currency:        c.Currency,
+		Processor:       processorNameAdyen,
+	}
+
+	return captured, nil
+}
+
+// Void will talk to adyen over http to cancel a payment, releasing the money it held, returning an error, if any
+func (ap AdyenProcessor) Void(ctx context.Context, v *payment.Void) (*payment.Voided, error) {
+
+	req := adyenModificationRequest{
+		MerchantAccount: ap.merchantAccount,
+		Reference:       v.ID,
+	}
+
+	var resp adyenModificationResponse
+	err := ap.post(ctx, fmt.Sprintf("/payments/%s/cancels", v.VendorReference), fmt.Sprintf("%s-void", v.ID), req, &resp)
+
+	if err != nil {
+		return nil, adyenModificationError("failed to cancel payment", err)
+	}
+
+	voided := &payment.Voided{
+		VendorReference: resp.PSPReference,
+		Processor:       processorNameAdyen,
+	}
+
+	return voided, nil
+}
+
+// Refund will talk to adyen over http to give back money taken by a payment, returning an error, if any
+func (ap AdyenProcessor) Refund(ctx context.Context, r *payment.Refund) (*payment.RefundSucceeded, error) {
+
+	req := adyenModificationRequest{
+		MerchantAccount: ap.merchantAccount,
+		Amount:          &adyenAmount{Currency: r.Currency, Value: r.Amount},
+		Reference:       r.ID,
+	}
+	if r.Reason != "" {
+		req.MerchantRefundReason = adyenRefundReason(r.Reason)
+	}
+
+	var resp adyenModificationResponse
+	err := ap.post(ctx, fmt.Sprintf("/payments/%s/refunds", r.VendorReference), fmt.Sprintf("%s-refund", r.ID), req, &resp)
+
+	if err != nil {
+		return nil, adyenModificationError("failed to refund payment", err)
+	}
+
+	succeeded := &payment.RefundSucceeded{
+		VendorReference: resp.PSPReference,
+		Amount:          r.Amount,
+		Currency:        r.Currency,
+		Processor:       processorNameAdyen,
+	}
+
+	return succeeded, nil
+}
+
+// adyenRefundReason translates the reason for a Refund to the merchantRefundReason adyen accepts
+func adyenRefundReason(reason string) string {
+	switch reason {
+	case "duplicate":
+		return "DUPLICATE"
+	case "fraudulent":
+		return "FRAUD"
+	case "requested_by_customer":
+		return "CUSTOMER REQUEST"
+	default:
+		return "OTHER"
+	}
+}
+
+// Confirm will talk to adyen over http to resume a payment with the result the payer was sent back with, returning an error, if any.
+// A payer who has not been sent back has not taken the action yet, so without the result the payment still requires it
+func (ap AdyenProcessor) Confirm(ctx context.Context, c *payment.Confirm) (*payment.Outcome, error) {
+
+	if c.RedirectResult == "" {
+		return &payment.Outcome{VendorReference: c.VendorReference, Status: payment.Outcome_REQUIRES_ACTION, Processor: processorNameAdyen}, nil
+	}
+
+	req := adyenDetailsRequest{Details: adyenDetails{RedirectResult: c.RedirectResult}}
+
+	var resp adyenPaymentResponse
+	err := ap.post(ctx, "/payments/details", fmt.Sprintf("%s-details", c.ID), req, &resp)
+
+	if err != nil {
+		return adyenFailure("failed to submit payment details", err, nil)
+	}
+
+	if resp.PSPReference == "" {
+		resp.PSPReference = c.VendorReference
+	}
+
+	return adyenOutcome(&resp, c.Amount, "", c.AutoCapture), nil
+}
+
+// Abandon has nothing to give up on, as adyen expires payments whose payer does not take the action they required
+func (ap AdyenProcessor) Abandon(ctx context.Context, c *payment.Confirm) error {
+	return nil
+}
+
+// adyenModificationError classifies an error from adyen when acting on an existing payment, those which may not happen
+// if the request is made again are a RetryableError and the rest are a Failure
+func adyenModificationError(action string, err error) error {
+
+	adyenErr, ok := err.(*adyenError)
+	if !ok || adyenErr.retryable() {
+		return payment.Retryable(fmt.Errorf("%s, %s", action, err.Error()))
+	}
+
+	code := adyenErr.ErrorType
+	if code == "" {
+		code = "processing_error"
+	}
+
+	return &payment.Failure{Code: code, Message: fmt.Sprintf("%s %s", adyenErr.ErrorCode, adyenErr.Message)}
+}
+
+// post sends a request to the Checkout api and reads its response, returning an error, if any, which is an adyenError
+// when adyen responded without carrying out the request
+func (ap AdyenProcessor) post(ctx context.Context, path, idempotencyKey string, body, into interface{}) error {
+
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request, %s", err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ap.baseURL+"/"+adyenAPIVersion+path, bytes.NewReader(buf))
+	if err != nil {
+		return fmt.Errorf("failed to create request, %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", ap.apiKey)
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := ap.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach adyen, %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response, %s", err.Error())
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		adyenErr := &adyenError{}
+		if err := json.Unmarshal(respBody, adyenErr); err != nil {
+			adyenErr.Message = string(respBody)
+		}
+		adyenErr.Status = resp.StatusCode
+
+		return adyenErr
+	}
+
+	err = json.Unmarshal(respBody, into)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response, %s", err.Error())
+	}
+
+	return nil
+}
+
+// NewAdyenProcessor is a factory for an AdyenProcessor configured by the options, returning an error if there is no key or merchant account
+func NewAdyenProcessor(opts AdyenOptions) (*AdyenProcessor, error) {
+
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("an adyen api key is required")
+	}
+	if opts.MerchantAccount == "" {
+		return nil, fmt.Errorf("an adyen merchant account is required")
+	}
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: opts.Timeout}
+	}
+
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = adyenTestURL
+	}
+
+	ap := AdyenProcessor{
+		client:          httpClient,
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		apiKey:          opts.APIKey,
+		merchantAccount: opts.MerchantAccount,
+		returnURL:       opts.ReturnURL,
+	}
+
+	return &ap, nil
+}
@@ -0,0 +1,313 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The states of a CircuitBreaker
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// BreakerOptions configures when a CircuitBreaker opens, and how it tests whether the processor has recovered
+type BreakerOptions struct {
+	// ErrorRate is the share of requests in a Window which can fail before the breaker opens, once there have been MinRequests
+	ErrorRate   float64
+	MinRequests int
+	Window      time.Duration
+	// OpenFor is how long the breaker stays open before letting HalfOpenRequests through, all of which need to succeed for it to close
+	OpenFor          time.Duration
+	HalfOpenRequests int
+	// OnStateChange is told whenever the breaker changes state, it is optional
+	OnStateChange func(*payment.BreakerStateChanged)
+}
+
+// CircuitBreaker is a processor which stops sending work to the processor it wraps once too much of it fails with a
+// RetryableError. While open it fails all work straight away with an UnsentError, which is retryable, so the work is
+// failed over to another processor or tried again later, rather than adding to the load on a processor which is down
+type CircuitBreaker struct {
+	name      string
+	processor PaymentProcessor
+	opts      BreakerOptions
+
+	mu          sync.Mutex
+	state       string
+	changedAt   time.Time
+	windowStart time.Time
+	requests    int
+	failures    int
+	probes      int
+	successes   int
+}
+
+// Name is the name of the processor the breaker is around
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// State is the state of the breaker, and when it changed to it
+func (cb *CircuitBreaker) State() (string, time.Time) {
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	// an open breaker is only moved to half open by the next request, but is already letting them through
+	if cb.state == BreakerOpen && time.Since(cb.changedAt) >= cb.opts.OpenFor {
+		return BreakerHalfOpen, cb.changedAt.Add(cb.opts.OpenFor)
+	}
+
+	return cb.state, cb.changedAt
+}
+
+// Process gives the Claim to the processor unless the breaker is open, returning an error, if any
+func (cb *CircuitBreaker) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+
+	probe, err := cb.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	outcome, err := cb.processor.Process(ctx, c)
+	cb.record(probe, err)
+
+	return outcome, err
+}
+
+// Authorize gives the Claim to the processor to hold the money unless the breaker is open, returning an error, if any
+func (cb *CircuitBreaker) Authorize(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+
+	probe, err := cb.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	outcome, err := cb.processor.Authorize(ctx, c)
+	cb.record(probe, err)
+
+	return outcome, err
+}
+
+// Capture sends the Capture to the processor unless the breaker is open, returning an error, if any
+func (cb *CircuitBreaker) Capture(ctx context.Context, c *payment.Capture) (*payment.Captured, error) {
+
+	probe, err := cb.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	captured, err := cb.processor.Capture(ctx, c)
+	cb.record(probe, err)
+
+	return captured, err
+}
+
+// Void sends the Void to the processor unless the breaker is open, returning an error, if any
+func (cb *CircuitBreaker) Void(ctx context.Context, v *payment.Void) (*payment.Voided, error) {
+
+	probe, err := cb.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	voided, err := cb.processor.Void(ctx, v)
+	cb.record(probe, err)
+
+	return voided, err
+}
+
+// Refund sends the Refund to the processor unless the breaker is open, returning an error, if any
+func (cb *CircuitBreaker) Refund(ctx context.Context, r *payment.Refund) (*payment.RefundSucceeded, error) {
+
+	probe, err := cb.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded, err := cb.processor.Refund(ctx, r)
+	cb.record(probe, err)
+
+	return succeeded, err
+}
+
+// Confirm sends the Confirm to the processor unless the breaker is open, returning an error, if any
+func (cb *CircuitBreaker) Confirm(ctx context.Context, c *payment.Confirm) (*payment.Outcome, error) {
+
+	probe, err := cb.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	outcome, err := cb.processor.Confirm(ctx, c)
+	cb.record(probe, err)
+
+	return outcome, err
+}
+
+// Abandon sends the Confirm to the processor to give up on it unless the breaker is open, returning an error, if any
+func (cb *CircuitBreaker) Abandon(ctx context.Context, c *payment.Confirm) error {
+
+	probe, err := cb.allow()
+	if err != nil {
+		return err
+	}
+
+	err = cb.processor.Abandon(ctx, c)
+	cb.record(probe, err)
+
+	return err
+}
+
+// allow reports whether work can be sent to the processor, and if it is a probe of whether the processor has recovered,
+// returning an UnsentError when the breaker is open, or half open with as many probes as it allows already in flight
+func (cb *CircuitBreaker) allow() (bool, error) {
+
+	cb.mu.Lock()
+	probe, allowed, changed := cb.admit(time.Now())
+	state := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(changed)
+
+	if !allowed {
+		return false, payment.Retryable(payment.Unsent(fmt.Errorf("the circuit breaker of processor %s is %s", cb.name, state)))
+	}
+
+	return probe, nil
+}
+
+// admit decides whether work can be sent to the processor at the time, and if it is a probe, along with the change
+// of state it caused, if any. It is called with the lock held
+func (cb *CircuitBreaker) admit(now time.Time) (probe, allowed bool, changed *payment.BreakerStateChanged) {
+
+	if cb.state == BreakerOpen && now.Sub(cb.changedAt) >= cb.opts.OpenFor {
+		changed = cb.change(BreakerHalfOpen, now)
+	}
+
+	switch cb.state {
+	case BreakerClosed:
+		if now.Sub(cb.windowStart) >= cb.opts.Window {
+			cb.windowStart, cb.requests, cb.failures = now, 0, 0
+		}
+		return false, true, changed
+	case BreakerHalfOpen:
+		if cb.probes+cb.successes < cb.opts.HalfOpenRequests {
+			cb.probes++
+			return true, true, changed
+		}
+	}
+
+	return false, false, changed
+}
+
+// record counts the result of work sent to the processor. A Failure is the processor working, such as by declining
+// a card, so only errors which may not happen again count against it
+func (cb *CircuitBreaker) record(probe bool, err error) {
+
+	cb.mu.Lock()
+	changed := cb.count(probe, payment.IsRetryable(err), time.Now())
+	cb.mu.Unlock()
+
+	cb.notify(changed)
+}
+
+// count records whether work failed, opening the breaker when too much has, and closing it once all of the probes
+// of a half open breaker have succeeded, returning the change of state, if any. It is called with the lock held
+func (cb *CircuitBreaker) count(probe, failed bool, now time.Time) *payment.BreakerStateChanged {
+
+	if probe && cb.state == BreakerHalfOpen {
+		// a probe from before the breaker last opened may finish after it is half open again
+		if cb.probes > 0 {
+			cb.probes--
+		}
+		switch {
+		case failed:
+			return cb.change(BreakerOpen, now)
+		case cb.successes+1 >= cb.opts.HalfOpenRequests:
+			return cb.change(BreakerClosed, now)
+		default:
+			cb.successes++
+			return nil
+		}
+	}
+
+	// work sent before the breaker opened says nothing about whether the processor has since recovered
+	if cb.state != BreakerClosed {
+		return nil
+	}
+
+	cb.requests++
+	if failed {
+		cb.failures++
+	}
+
+	if cb.requests >= cb.opts.MinRequests && cb.errorRate() >= cb.opts.ErrorRate {
+		return cb.change(BreakerOpen, now)
+	}
+
+	return nil
+}
+
+// change moves the breaker to the state at the time, returning the event describing the change. It is called with the lock held
+func (cb *CircuitBreaker) change(to string, now time.Time) *payment.BreakerStateChanged {
+
+	changed := &payment.BreakerStateChanged{
+		Processor: cb.name,
+		From:      cb.state,
+		To:        to,
+		ErrorRate: cb.errorRate(),
+		Requests:  int64(cb.requests),
+		ChangedAt: timestamppb.New(now),
+	}
+
+	cb.state = to
+	cb.changedAt = now
+	cb.probes, cb.successes = 0, 0
+	if to == BreakerClosed {
+		cb.windowStart, cb.requests, cb.failures = now, 0, 0
+	}
+
+	return changed
+}
+
+// notify tells OnStateChange about a change of state, if there was one, once the lock has been released
+func (cb *CircuitBreaker) notify(changed *payment.BreakerStateChanged) {
+	if changed != nil && cb.opts.OnStateChange != nil {
+		cb.opts.OnStateChange(changed)
+	}
+}
+
+// errorRate is the share of the requests in the window which failed
+func (cb *CircuitBreaker) errorRate() float64 {
+	if cb.requests == 0 {
+		return 0
+	}
+	return float64(cb.failures) / float64(cb.requests)
+}
+
+// NewCircuitBreaker is a factory for a closed CircuitBreaker around the processor with the name
+func NewCircuitBreaker(name string, p PaymentProcessor, opts BreakerOptions) *CircuitBreaker {
+
+	if opts.HalfOpenRequests < 1 {
+		opts.HalfOpenRequests = 1
+	}
+
+	now := time.Now()
+
+	return &CircuitBreaker{
+		name:        name,
+		processor:   p,
+		opts:        opts,
+		state:       BreakerClosed,
+		changedAt:   now,
+		windowStart: now,
+	}
+}
@@ -0,0 +1,226 @@
+package processor
+
+import (
+	"testing"
+	"time"
+)
+
+// t0 is when the breakers under test last changed state and started their window
+var t0 = time.Date(2021, 2, 1, 12, 0, 0, 0, time.UTC)
+
+// counters are the state of a breaker which admit and count read and change
+type counters struct {
+	state     string
+	requests  int
+	failures  int
+	probes    int
+	successes int
+}
+
+func testBreaker(c counters) *CircuitBreaker {
+
+	cb := NewCircuitBreaker("stripe", nil, BreakerOptions{
+		ErrorRate:        0.5,
+		MinRequests:      4,
+		Window:           time.Minute,
+		OpenFor:          30 * time.Second,
+		HalfOpenRequests: 2,
+	})
+
+	cb.changedAt, cb.windowStart = t0, t0
+	cb.state, cb.requests, cb.failures, cb.probes, cb.successes = c.state, c.requests, c.failures, c.probes, c.successes
+
+	return cb
+}
+
+func (cb *CircuitBreaker) counters() counters {
+	return counters{cb.state, cb.requests, cb.failures, cb.probes, cb.successes}
+}
+
+func TestCircuitBreakerAdmit(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		before  counters
+		at      time.Duration
+		probe   bool
+		allowed bool
+		changed string
+		after   counters
+	}{
+		{
+			name:    "closed within the window",
+			before:  counters{state: BreakerClosed, requests: 3, failures: 1},
+			at:      10 * time.Second,
+			allowed: true,
+			after:   counters{state: BreakerClosed, requests: 3, failures: 1},
+		},
+		{
+			name:    "closed once the window has passed",
+			before:  counters{state: BreakerClosed, requests: 3, failures: 1},
+			at:      time.Minute,
+			allowed: true,
+			after:   counters{state: BreakerClosed},
+		},
+		{
+			name:   "open",
+			before: counters{state: BreakerOpen, requests: 4, failures: 2},
+			at:     29 * time.Second,
+			after:  counters{state: BreakerOpen, requests: 4, failures: 2},
+		},
+		{
+			name:    "open for long enough",
+			before:  counters{state: BreakerOpen, requests: 4, failures: 2},
+			at:      30 * time.Second,
+			probe:   true,
+			allowed: true,
+			changed: BreakerHalfOpen,
+			after:   counters{state: BreakerHalfOpen, requests: 4, failures: 2, probes: 1},
+		},
+		{
+			name:    "half open with a probe in flight",
+			before:  counters{state: BreakerHalfOpen, probes: 1},
+			at:      time.Minute,
+			probe:   true,
+			allowed: true,
+			after:   counters{state: BreakerHalfOpen, probes: 2},
+		},
+		{
+			name:   "half open with every probe in flight",
+			before: counters{state: BreakerHalfOpen, probes: 2},
+			at:     time.Minute,
+			after:  counters{state: BreakerHalfOpen, probes: 2},
+		},
+		{
+			name:   "half open with a probe succeeded and one in flight",
+			before: counters{state: BreakerHalfOpen, probes: 1, successes: 1},
+			at:     time.Minute,
+			after:  counters{state: BreakerHalfOpen, probes: 1, successes: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			cb := testBreaker(tt.before)
+
+			probe, allowed, changed := cb.admit(t0.Add(tt.at))
+
+			if probe != tt.probe || allowed != tt.allowed {
+				t.Errorf("expected probe %t and allowed %t, got %t and %t", tt.probe, tt.allowed, probe, allowed)
+			}
+			assertChanged(t, changed.GetTo(), tt.changed, cb, t0.Add(tt.at))
+			if cb.counters() != tt.after {
+				t.Errorf("expected %+v, got %+v", tt.after, cb.counters())
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerCount(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		before  counters
+		probe   bool
+		failed  bool
+		changed string
+		after   counters
+	}{
+		{
+			name:   "closed success",
+			before: counters{state: BreakerClosed},
+			after:  counters{state: BreakerClosed, requests: 1},
+		},
+		{
+			name:   "closed failure with too few requests",
+			before: counters{state: BreakerClosed, requests: 2, failures: 2},
+			failed: true,
+			after:  counters{state: BreakerClosed, requests: 3, failures: 3},
+		},
+		{
+			name:   "closed success keeping the error rate below the limit",
+			before: counters{state: BreakerClosed, requests: 3, failures: 1},
+			after:  counters{state: BreakerClosed, requests: 4, failures: 1},
+		},
+		{
+			name:    "closed failure taking the error rate to the limit",
+			before:  counters{state: BreakerClosed, requests: 3, failures: 1},
+			failed:  true,
+			changed: BreakerOpen,
+			after:   counters{state: BreakerOpen, requests: 4, failures: 2},
+		},
+		{
+			name:   "open failure sent before it opened",
+			before: counters{state: BreakerOpen, requests: 4, failures: 2},
+			failed: true,
+			after:  counters{state: BreakerOpen, requests: 4, failures: 2},
+		},
+		{
+			name:   "half open failure sent before it opened",
+			before: counters{state: BreakerHalfOpen, probes: 1},
+			failed: true,
+			after:  counters{state: BreakerHalfOpen, probes: 1},
+		},
+		{
+			name:    "half open probe failed",
+			before:  counters{state: BreakerHalfOpen, probes: 2, successes: 0},
+			probe:   true,
+			failed:  true,
+			changed: BreakerOpen,
+			after:   counters{state: BreakerOpen},
+		},
+		{
+			name:   "half open probe succeeded with another in flight",
+			before: counters{state: BreakerHalfOpen, probes: 2},
+			probe:  true,
+			after:  counters{state: BreakerHalfOpen, probes: 1, successes: 1},
+		},
+		{
+			name:    "half open last probe succeeded",
+			before:  counters{state: BreakerHalfOpen, requests: 4, failures: 2, probes: 1, successes: 1},
+			probe:   true,
+			changed: BreakerClosed,
+			after:   counters{state: BreakerClosed},
+		},
+		{
+			name:   "closed probe from before it last opened",
+			before: counters{state: BreakerClosed, requests: 1},
+			probe:  true,
+			after:  counters{state: BreakerClosed, requests: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			cb := testBreaker(tt.before)
+			now := t0.Add(10 * time.Second)
+
+			changed := cb.count(tt.probe, tt.failed, now)
+
+			assertChanged(t, changed.GetTo(), tt.changed, cb, now)
+			if cb.counters() != tt.after {
+				t.Errorf("expected %+v, got %+v", tt.after, cb.counters())
+			}
+		})
+	}
+}
+
+// assertChanged checks the breaker changed to the expected state at the time, or did not change when none is expected
+func assertChanged(t *testing.T, to, expected string, cb *CircuitBreaker, now time.Time) {
+
+	t.Helper()
+
+	if to != expected {
+		t.Errorf("expected a change to %q, got %q", expected, to)
+	}
+
+	changedAt := t0
+	if expected != "" {
+		changedAt = now
+	}
+	if !cb.changedAt.Equal(changedAt) {
+		t.Errorf("expected the breaker to have changed at %s, got %s", changedAt, cb.changedAt)
+	}
+}
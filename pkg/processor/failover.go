@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FailoverProcessor gives each Claim to the first of an ordered list of named processors, moving on to the next only
+// when one fails with an UnsentError, as the Claim never reached it and so cannot have been charged. Every processor
+// the Claim was given to is recorded in the Attempts of the Outcome, and its Processor is the name of the one which processed it.
+// A Claim which reached a processor before it was retried may have been charged by it, so it is only given to that one again
+type FailoverProcessor struct {
+	names      []string
+	processors map[string]PaymentProcessor
+}
+
+// Process gives the Claim to each processor in turn until one has processed it, returning an error, if any
+func (fp FailoverProcessor) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return fp.attempt(ctx, c, PaymentProcessor.Process)
+}
+
+// Authorize gives the Claim to each processor in turn until one has held the money, returning an error, if any
+func (fp FailoverProcessor) Authorize(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return fp.attempt(ctx, c, PaymentProcessor.Authorize)
+}
+
+// attempt does the work with each processor in turn until one does not fail with an UnsentError. When all of them do,
+// the errors are returned as an UnsentError, so the work can be retried later. Work which may succeed if it is retried
+// fails with an AttemptedError, so the attempts made and the processor the Claim reached are not lost
+func (fp FailoverProcessor) attempt(ctx context.Context, c *payment.Claim, work func(PaymentProcessor, context.Context, *payment.Claim) (*payment.Outcome, error)) (*payment.Outcome, error) {
+
+	names := fp.names
+	if reached := payment.Reached(ctx); reached != "" {
+		if _, ok := fp.processors[reached]; !ok {
+			return nil, &payment.Failure{Code: "processor_not_found", Message: fmt.Sprintf("there is no processor named %s", reached)}
+		}
+		// the processor recognises the Claim by its idempotency key, another would charge it again
+		names = []string{reached}
+	}
+
+	var attempts []*payment.Outcome_Attempt
+	var unsent []string
+
+	for _, name := range names {
+
+		attempt := &payment.Outcome_Attempt{Processor: name, AttemptedAt: timestamppb.Now()}
+		attempts = append(attempts, attempt)
+
+		outcome, err := work(fp.processors[name], ctx, c)
+
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+
+		if payment.IsUnsent(err) {
+			unsent = append(unsent, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+
+		if payment.IsRetryable(err) {
+			return nil, payment.Retryable(&payment.AttemptedError{Err: errors.Unwrap(err), Attempts: attempts, Reached: name})
+		}
+
+		if outcome != nil {
+			outcome.Processor = name
+			outcome.Attempts = attempts
+		}
+
+		return outcome, err
+	}
+
+	err := payment.Unsent(fmt.Errorf("no processor was reached, %s", strings.Join(unsent, "; ")))
+
+	return nil, payment.Retryable(&payment.AttemptedError{Err: err, Attempts: attempts})
+}
+
+// NewFailoverProcessor is a factory for a FailoverProcessor over the named processors, in order, returning an error if
+// there are none or any of them are not in the registry
+func NewFailoverProcessor(processors map[string]PaymentProcessor, names []string) (*FailoverProcessor, error) {
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("there are no processors to fail over between")
+	}
+
+	for _, name := range names {
+		if _, ok := processors[name]; !ok {
+			return nil, fmt.Errorf("processor %q is not in the registry", name)
+		}
+	}
+
+	return &FailoverProcessor{names: names, processors: processors}, nil
+}
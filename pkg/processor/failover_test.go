@@ -0,0 +1,179 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+var (
+	errUnsent    = payment.Retryable(payment.Unsent(errors.New("connection refused")))
+	errRetryable = payment.Retryable(errors.New("service unavailable"))
+	errFailure   = &payment.Failure{Code: "card_declined", Message: "the card was declined"}
+)
+
+// stubProcessor processes Claims by failing with its error, or successfully when it has none, recording
+// its name in the calls each time it is given one
+type stubProcessor struct {
+	PaymentProcessor
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (sp stubProcessor) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+
+	*sp.calls = append(*sp.calls, sp.name)
+	if sp.err != nil {
+		return nil, sp.err
+	}
+
+	return &payment.Outcome{Success: true, Status: payment.Outcome_SUCCEEDED}, nil
+}
+
+// stubProcessors are a registry of stubProcessors with the names, failing with the errors keyed by their names,
+// which all record the calls made to them in order
+func stubProcessors(names []string, errs map[string]error, calls *[]string) map[string]PaymentProcessor {
+
+	processors := make(map[string]PaymentProcessor, len(names))
+	for _, name := range names {
+		processors[name] = stubProcessor{name: name, err: errs[name], calls: calls}
+	}
+
+	return processors
+}
+
+// processed is what processing a Claim is expected to result in
+type processed struct {
+	called    []string
+	processor string
+	attempts  int
+	// reached is the processor an AttemptedError was reached by
+	reached   string
+	unsent    bool
+	retryable bool
+	failure   string
+}
+
+// assertProcessed checks the processors called, and the Outcome or error processing the Claim resulted in, are as expected
+func assertProcessed(t *testing.T, expected processed, called []string, outcome *payment.Outcome, err error) {
+
+	t.Helper()
+
+	if !reflect.DeepEqual(called, expected.called) {
+		t.Errorf("expected the claim to be given to %v, it was given to %v", expected.called, called)
+	}
+
+	if payment.IsRetryable(err) != expected.retryable || payment.IsUnsent(err) != expected.unsent {
+		t.Errorf("expected a retryable %t, unsent %t error, got %v", expected.retryable, expected.unsent, err)
+	}
+
+	var attempted *payment.AttemptedError
+	if errors.As(err, &attempted) {
+		if len(attempted.Attempts) != expected.attempts || attempted.Reached != expected.reached {
+			t.Errorf("expected %d attempts reaching %q, got %d reaching %q", expected.attempts, expected.reached, len(attempted.Attempts), attempted.Reached)
+		}
+	} else if expected.retryable {
+		t.Errorf("expected an AttemptedError, got %v", err)
+	}
+
+	var failure *payment.Failure
+	if errors.As(err, &failure) != (expected.failure != "") || (failure != nil && failure.Code != expected.failure) {
+		t.Errorf("expected failure %q, got %v", expected.failure, err)
+	}
+
+	if expected.processor == "" {
+		if outcome != nil {
+			t.Errorf("expected no outcome, got %+v", outcome)
+		}
+		return
+	}
+
+	if outcome == nil {
+		t.Fatalf("expected an outcome from %s, got %v", expected.processor, err)
+	}
+	if outcome.Processor != expected.processor || len(outcome.Attempts) != expected.attempts {
+		t.Errorf("expected an outcome from %s after %d attempts, got one from %s after %d", expected.processor, expected.attempts, outcome.Processor, len(outcome.Attempts))
+	}
+}
+
+func TestFailoverProcessor(t *testing.T) {
+
+	names := []string{"stripe", "adyen", "stripe_us"}
+
+	tests := []struct {
+		name     string
+		errs     map[string]error
+		reached  string
+		expected processed
+	}{
+		{
+			name:     "processed by the first",
+			expected: processed{called: []string{"stripe"}, processor: "stripe", attempts: 1},
+		},
+		{
+			name:     "unsent fails over to the next",
+			errs:     map[string]error{"stripe": errUnsent},
+			expected: processed{called: []string{"stripe", "adyen"}, processor: "adyen", attempts: 2},
+		},
+		{
+			name:     "unsent by every processor",
+			errs:     map[string]error{"stripe": errUnsent, "adyen": errUnsent, "stripe_us": errUnsent},
+			expected: processed{called: names, attempts: 3, unsent: true, retryable: true},
+		},
+		{
+			name:     "retryable does not fail over",
+			errs:     map[string]error{"stripe": errRetryable},
+			expected: processed{called: []string{"stripe"}, attempts: 1, reached: "stripe", retryable: true},
+		},
+		{
+			name:     "unsent then retryable",
+			errs:     map[string]error{"stripe": errUnsent, "adyen": errRetryable},
+			expected: processed{called: []string{"stripe", "adyen"}, attempts: 2, reached: "adyen", retryable: true},
+		},
+		{
+			name:     "failure does not fail over",
+			errs:     map[string]error{"stripe": errFailure},
+			expected: processed{called: []string{"stripe"}, failure: "card_declined"},
+		},
+		{
+			name:     "reached is the only processor given the claim",
+			reached:  "adyen",
+			expected: processed{called: []string{"adyen"}, processor: "adyen", attempts: 1},
+		},
+		{
+			name:     "reached does not fail over when unsent",
+			errs:     map[string]error{"adyen": errUnsent},
+			reached:  "adyen",
+			expected: processed{called: []string{"adyen"}, attempts: 1, unsent: true, retryable: true},
+		},
+		{
+			name:     "reached is not in the registry",
+			reached:  "worldpay",
+			expected: processed{failure: "processor_not_found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var called []string
+			fp, err := NewFailoverProcessor(stubProcessors(names, tt.errs, &called), names)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			if tt.reached != "" {
+				ctx = payment.WithReached(ctx, tt.reached)
+			}
+
+			outcome, err := fp.Process(ctx, &payment.Claim{ID: "claim"})
+
+			assertProcessed(t, tt.expected, called, outcome, err)
+		})
+	}
+}
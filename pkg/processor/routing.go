@@ -0,0 +1,248 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// maxBINLength is the length of the longest prefix of a card number which is looked up to find the country it was issued in
+const maxBINLength = 8
+
+// PaymentProcessor is able to carry out every command for a payment
+type PaymentProcessor interface {
+	payment.Processor
+	payment.Refunder
+	payment.Authorizer
+	payment.Confirmer
+}
+
+// Route sends the Claims which match all of its criteria to the named processor, criteria left empty match every Claim
+type Route struct {
+	Processor string
+	// Failover are the processors, in order, which the Claims are given to when they cannot be sent to the processor
+	Failover   []string
+	Payees     []string
+	Currencies []string
+	Brands     []string
+	// Countries are where the card was issued, found from the leading digits of its number
+	Countries []string
+	// MinAmount and MaxAmount bound the amount in minor units, a MaxAmount of 0 leaves it unbounded
+	MinAmount int64
+	MaxAmount int64
+}
+
+// matches reports whether a Claim, whose card was issued in the country, meets all the criteria of the Route
+func (r Route) matches(c *payment.Claim, country string) bool {
+
+	amount := c.Amount.GetValue()
+
+	return contains(r.Payees, c.Payee) &&
+		contains(r.Currencies, c.Amount.GetCurrency()) &&
+		contains(r.Brands, payment.CardBrand(c.Payer.GetNumber())) &&
+		contains(r.Countries, country) &&
+		amount >= r.MinAmount &&
+		(r.MaxAmount == 0 || amount <= r.MaxAmount)
+}
+
+// contains reports whether the value is one of the values, which it always is when there are none
+func contains(values []string, value string) bool {
+
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoutingOptions configures how a RoutingProcessor picks a processor
+type RoutingOptions struct {
+	// Default is the name of the processor for Claims which match none of the Routes
+	Default string
+	// Failover are the processors, in order, which the Claims for the default are given to when they cannot be sent to it
+	Failover []string
+	Routes   []Route
+	// BINCountries is the country each range of cards was issued in, keyed by the leading digits of their numbers
+	BINCountries map[string]string
+}
+
+// RoutingProcessor is a Processor which picks one of a registry of named processors for each Claim, by the first Route
+// the Claim matches, failing over to the processors after it when the Claim cannot be sent to it. The name of the processor
+// which processed the Claim is recorded on the Outcome, and everything done to the payment afterwards is sent to the
+// processor with that name
+type RoutingProcessor struct {
+	processors   map[string]PaymentProcessor
+	routes       []Route
+	failovers    []*FailoverProcessor
+	fallback     string
+	failover     *FailoverProcessor
+	binCountries map[string]string
+}
+
+// Process gives the Claim to the processors it is routed to, returning an error, if any
+func (rp RoutingProcessor) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return rp.route(c).Process(ctx, c)
+}
+
+// Authorize gives the Claim to the processors it is routed to, to hold the money, returning an error, if any
+func (rp RoutingProcessor) Authorize(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return rp.route(c).Authorize(ctx, c)
+}
+
+// Capture sends the Capture to the processor which authorised the payment, returning an error, if any
+func (rp RoutingProcessor) Capture(ctx context.Context, c *payment.Capture) (*payment.Captured, error) {
+
+	p, name, err := rp.named(c.Processor)
+	if err != nil {
+		return nil, err
+	}
+
+	captured, err := p.Capture(ctx, c)
+	if captured != nil {
+		captured.Processor = name
+	}
+
+	return captured, err
+}
+
+// Void sends the Void to the processor which authorised the payment, returning an error, if any
+func (rp RoutingProcessor) Void(ctx context.Context, v *payment.Void) (*payment.Voided, error) {
+
+	p, name, err := rp.named(v.Processor)
+	if err != nil {
+		return nil, err
+	}
+
+	voided, err := p.Void(ctx, v)
+	if voided != nil {
+		voided.Processor = name
+	}
+
+	return voided, err
+}
+
+// Refund sends the Refund to the processor which took the payment, returning an error, if any
+func (rp RoutingProcessor) Refund(ctx context.Context, r *payment.Refund) (*payment.RefundSucceeded, error) {
+
+	p, name, err := rp.named(r.Processor)
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded, err := p.Refund(ctx, r)
+	if succeeded != nil {
+		succeeded.Processor = name
+	}
+
+	return succeeded, err
+}
+
+// Confirm sends the Confirm to the processor which asked the payer to take action, returning an error, if any
+func (rp RoutingProcessor) Confirm(ctx context.Context, c *payment.Confirm) (*payment.Outcome, error) {
+
+	p, name, err := rp.named(c.Processor)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome, err := p.Confirm(ctx, c)
+	if outcome != nil {
+		outcome.Processor = name
+	}
+
+	return outcome, err
+}
+
+// Abandon sends the Confirm to the processor which asked the payer to take action, to give up on it, returning an error, if any
+func (rp RoutingProcessor) Abandon(ctx context.Context, c *payment.Confirm) error {
+
+	p, _, err := rp.named(c.Processor)
+	if err != nil {
+		return err
+	}
+
+	return p.Abandon(ctx, c)
+}
+
+// route is the processors for a Claim, from the first Route it matches
+func (rp RoutingProcessor) route(c *payment.Claim) *FailoverProcessor {
+
+	country := rp.country(c.Payer.GetNumber())
+
+	for i, r := range rp.routes {
+		if r.matches(c, country) {
+			return rp.failovers[i]
+		}
+	}
+
+	return rp.failover
+}
+
+// country is where a card was issued, from the longest range of leading digits of its number which is known, or empty if none are
+func (rp RoutingProcessor) country(number string) string {
+
+	n := len(number)
+	if n > maxBINLength {
+		n = maxBINLength
+	}
+
+	for ; n > 0; n-- {
+		if country, ok := rp.binCountries[number[:n]]; ok {
+			return country
+		}
+	}
+
+	return ""
+}
+
+// named is the processor with the name, or the default for payments which were not given to a named processor,
+// returning a Failure if there is no processor with the name
+func (rp RoutingProcessor) named(name string) (PaymentProcessor, string, error) {
+
+	if name == "" {
+		name = rp.fallback
+	}
+
+	p, ok := rp.processors[name]
+	if !ok {
+		return nil, name, &payment.Failure{Code: "processor_not_found", Message: fmt.Sprintf("there is no processor named %s", name)}
+	}
+
+	return p, name, nil
+}
+
+// NewRoutingProcessor is a factory for a RoutingProcessor choosing between the named processors, returning an error
+// if the default, or the processor of any Route, or any they fail over to, is not one of them
+func NewRoutingProcessor(processors map[string]PaymentProcessor, opts RoutingOptions) (*RoutingProcessor, error) {
+
+	failover, err := NewFailoverProcessor(processors, append([]string{opts.Default}, opts.Failover...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the default processor, %s", err.Error())
+	}
+
+	failovers := make([]*FailoverProcessor, len(opts.Routes))
+	for i, r := range opts.Routes {
+		failovers[i], err = NewFailoverProcessor(processors, append([]string{r.Processor}, r.Failover...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the processor of route %d, %s", i, err.Error())
+		}
+	}
+
+	rp := RoutingProcessor{
+		processors:   processors,
+		routes:       opts.Routes,
+		failovers:    failovers,
+		fallback:     opts.Default,
+		failover:     failover,
+		binCountries: opts.BINCountries,
+	}
+
+	return &rp, nil
+}
@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+func TestRoutingProcessor(t *testing.T) {
+
+	names := []string{"stripe", "adyen", "stripe_us"}
+	opts := RoutingOptions{
+		Default:  "stripe",
+		Failover: []string{"adyen"},
+		Routes: []Route{
+			{Processor: "adyen", Currencies: []string{"EUR"}, Failover: []string{"stripe"}},
+			{Processor: "stripe_us", Countries: []string{"US"}},
+		},
+		BINCountries: map[string]string{"4242": "GB", "424242": "US"},
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		card     string
+		errs     map[string]error
+		reached  string
+		expected processed
+	}{
+		{
+			name:     "default",
+			currency: "GBP",
+			card:     "4000000000000077",
+			expected: processed{called: []string{"stripe"}, processor: "stripe", attempts: 1},
+		},
+		{
+			name:     "default fails over when unsent",
+			currency: "GBP",
+			card:     "4000000000000077",
+			errs:     map[string]error{"stripe": errUnsent},
+			expected: processed{called: []string{"stripe", "adyen"}, processor: "adyen", attempts: 2},
+		},
+		{
+			name:     "default does not fail over when retryable",
+			currency: "GBP",
+			card:     "4000000000000077",
+			errs:     map[string]error{"stripe": errRetryable},
+			expected: processed{called: []string{"stripe"}, attempts: 1, reached: "stripe", retryable: true},
+		},
+		{
+			name:     "routed by currency",
+			currency: "EUR",
+			card:     "4000000000000077",
+			expected: processed{called: []string{"adyen"}, processor: "adyen", attempts: 1},
+		},
+		{
+			name:     "route fails over to its own processors",
+			currency: "EUR",
+			card:     "4000000000000077",
+			errs:     map[string]error{"adyen": errUnsent},
+			expected: processed{called: []string{"adyen", "stripe"}, processor: "stripe", attempts: 2},
+		},
+		{
+			name:     "routed by the longest bin",
+			currency: "GBP",
+			card:     "4242424242424242",
+			expected: processed{called: []string{"stripe_us"}, processor: "stripe_us", attempts: 1},
+		},
+		{
+			name:     "first matching route",
+			currency: "EUR",
+			card:     "4242424242424242",
+			expected: processed{called: []string{"adyen"}, processor: "adyen", attempts: 1},
+		},
+		{
+			name:     "reached skips the route",
+			currency: "EUR",
+			card:     "4000000000000077",
+			errs:     map[string]error{"stripe_us": errUnsent},
+			reached:  "stripe_us",
+			expected: processed{called: []string{"stripe_us"}, attempts: 1, unsent: true, retryable: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var called []string
+			rp, err := NewRoutingProcessor(stubProcessors(names, tt.errs, &called), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			if tt.reached != "" {
+				ctx = payment.WithReached(ctx, tt.reached)
+			}
+
+			claim := &payment.Claim{
+				ID:     "claim",
+				Amount: &payment.Claim_MonetaryAmount{Currency: tt.currency, Value: 1000},
+				Payer:  &payment.Claim_Card{Number: tt.card},
+			}
+
+			outcome, err := rp.Process(ctx, claim)
+
+			assertProcessed(t, tt.expected, called, outcome, err)
+		})
+	}
+}
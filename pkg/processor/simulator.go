@@ -0,0 +1,388 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const processorNameSimulator = "simulator"
+
+// The amounts, in minor units, which the simulator treats as asking for a behaviour of the processor rather than a payment
+const (
+	// SimulatorAmountTimeout is never answered, so the request times out
+	SimulatorAmountTimeout = 9901
+	// SimulatorAmountSlow is answered after the slow response time
+	SimulatorAmountSlow = 9902
+	// SimulatorAmountFlaky fails with a RetryableError half of the time
+	SimulatorAmountFlaky = 9903
+	// SimulatorAmountUnreachable never reaches the processor, so fails with an UnsentError
+	SimulatorAmountUnreachable = 9904
+)
+
+// simulatorCard is what the simulator does with a card, cards it does not know are charged
+type simulatorCard struct {
+	status      payment.Outcome_Status
+	failureCode string
+	declineCode string
+	message     string
+	// authenticates is whether a card which requires action passes authentication when it is confirmed
+	authenticates bool
+}
+
+// simulatorCards are the magic card numbers of the simulator, which are those stripe uses for testing
+var simulatorCards = map[string]simulatorCard{
+	"4000000000000002": {status: payment.Outcome_DECLINED, failureCode: "card_declined", declineCode: "generic_decline", message: "Your card was declined."},
+	"4000000000009995": {status: payment.Outcome_DECLINED, failureCode: "card_declined", declineCode: "insufficient_funds", message: "Your card has insufficient funds."},
+	"4000000000000069": {status: payment.Outcome_DECLINED, failureCode: "expired_card", declineCode: "expired_card", message: "Your card has expired."},
+	"4000000000000119": {status: payment.Outcome_ERROR, failureCode: "processing_error", message: "An error occurred while processing your card."},
+	"4000002760003184": {status: payment.Outcome_REQUIRES_ACTION, authenticates: true},
+	"4000008260003178": {status: payment.Outcome_REQUIRES_ACTION, failureCode: "payment_intent_authentication_failure", message: "The payer failed to authenticate."},
+}
+
+// simulatorPayment is a payment the simulator has taken, it is only remembered for as long as the process runs
+type simulatorPayment struct {
+	number    string
+	currency  string
+	amount    int64
+	capture   bool
+	outcome   *payment.Outcome
+	captured  int64
+	refunded  int64
+	cancelled bool
+}
+
+// SimulatorOptions configures how a SimulatorProcessor behaves
+type SimulatorOptions struct {
+	// Latency is how long every request takes to be answered
+	Latency time.Duration
+	// SlowResponse is how long a request for SimulatorAmountSlow takes to be answered
+	SlowResponse time.Duration
+	// FailureRate is the share of every request which fails at random with a RetryableError
+	FailureRate float64
+	// Seed makes the random failures happen in the same order each time the simulator is started
+	Seed int64
+	// ReturnURL is where the payer is sent to authenticate, as the simulator takes the payer to have done so straight away
+	ReturnURL string
+}
+
+// SimulatorProcessor is a processor which runs in the process, without talking to a Payment Service Provider. Payments
+// are taken unless their card number or amount is one which asks for another outcome, such as a decline, or a behaviour
+// of the processor, such as timing out, so that everything can be run and tested without depending on anything else
+type SimulatorProcessor struct {
+	opts SimulatorOptions
+
+	mu       sync.Mutex
+	random   *rand.Rand
+	payments map[string]*simulatorPayment
+}
+
+// Process takes the payment for the Claim, returning an error, if any
+func (sp *SimulatorProcessor) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return sp.pay(ctx, c, true)
+}
+
+// Authorize holds the money for the Claim to be captured later, returning an error, if any
+func (sp *SimulatorProcessor) Authorize(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return sp.pay(ctx, c, false)
+}
+
+func (sp *SimulatorProcessor) pay(ctx context.Context, c *payment.Claim, capture bool) (*payment.Outcome, error) {
+
+	switch c.Amount.GetValue() {
+	case SimulatorAmountTimeout:
+		<-ctx.Done()
+		return nil, payment.Retryable(fmt.Errorf("simulator did not respond, %s", ctx.Err().Error()))
+	case SimulatorAmountSlow:
+		if err := sp.wait(ctx, sp.opts.SlowResponse); err != nil {
+			return nil, err
+		}
+	case SimulatorAmountFlaky:
+		if sp.chance(0.5) {
+			return nil, payment.Retryable(fmt.Errorf("simulator failed at random"))
+		}
+	case SimulatorAmountUnreachable:
+		return nil, payment.Retryable(payment.Unsent(fmt.Errorf("simulator could not be reached")))
+	}
+
+	if err := sp.simulate(ctx); err != nil {
+		return nil, err
+	}
+
+	reference := "sim_" + c.ID
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	// a claim made again is answered as it was the first time, as a Payment Service Provider does for an idempotency key
+	if p, ok := sp.payments[reference]; ok {
+		return clone(p.outcome), nil
+	}
+
+	p := &simulatorPayment{
+		number:   c.Payer.GetNumber(),
+		currency: strings.ToUpper(c.Amount.GetCurrency()),
+		amount:   c.Amount.GetValue(),
+		capture:  capture,
+	}
+
+	outcome := &payment.Outcome{
+		VendorReference: reference,
+		Amount:          p.amount,
+		Currency:        p.currency,
+		Processor:       processorNameSimulator,
+		VendorCreatedAt: timestamppb.Now(),
+		Card:            &payment.Outcome_Card{Brand: payment.CardBrand(p.number), Last4: last4(p.number), Funding: "credit"},
+	}
+
+	card, ok := simulatorCards[p.number]
+	switch {
+	case !ok:
+		sp.succeed(p, outcome)
+	case card.status == payment.Outcome_REQUIRES_ACTION:
+		outcome.Status = payment.Outcome_REQUIRES_ACTION
+		outcome.NextAction = "redirect_to_url"
+		outcome.RedirectUrl = strings.Replace(sp.opts.ReturnURL, "{id}", c.ID, -1)
+		outcome.ClientSecret = reference + "_secret"
+	default:
+		outcome.Status = card.status
+		outcome.FailureCode = card.failureCode
+		outcome.DeclineCode = card.declineCode
+		outcome.FailureMessage = card.message
+		outcome.Risk = &payment.Outcome_Risk{NetworkStatus: "declined_by_network", Level: "normal"}
+	}
+
+	p.outcome = outcome
+	sp.payments[reference] = p
+
+	return clone(outcome), nil
+}
+
+// succeed takes or holds the money for a payment
+func (sp *SimulatorProcessor) succeed(p *simulatorPayment, outcome *payment.Outcome) {
+
+	outcome.Success = true
+	outcome.Status = payment.Outcome_AUTHORISED
+	outcome.FailureCode, outcome.DeclineCode, outcome.FailureMessage = "", "", ""
+	outcome.NextAction, outcome.RedirectUrl, outcome.ClientSecret = "", "", ""
+	outcome.Risk = &payment.Outcome_Risk{NetworkStatus: "approved_by_network", Level: "normal"}
+
+	if p.capture {
+		outcome.Status = payment.Outcome_SUCCEEDED
+		outcome.AmountCaptured = p.amount
+		p.captured = p.amount
+	}
+}
+
+// Confirm finishes a payment which required the payer to authenticate, which they pass or fail depending on the card
+func (sp *SimulatorProcessor) Confirm(ctx context.Context, c *payment.Confirm) (*payment.Outcome, error) {
+
+	if err := sp.simulate(ctx); err != nil {
+		return nil, err
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	p, err := sp.find(c.VendorReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.outcome.Status != payment.Outcome_REQUIRES_ACTION || p.cancelled {
+		return clone(p.outcome), nil
+	}
+
+	card := simulatorCards[p.number]
+	if card.authenticates {
+		sp.succeed(p, p.outcome)
+	} else {
+		p.outcome.Status = payment.Outcome_DECLINED
+		p.outcome.FailureCode = card.failureCode
+		p.outcome.FailureMessage = card.message
+		p.outcome.NextAction, p.outcome.RedirectUrl, p.outcome.ClientSecret = "", "", ""
+	}
+
+	return clone(p.outcome), nil
+}
+
+// Abandon cancels a payment which is waiting on the payer to authenticate
+func (sp *SimulatorProcessor) Abandon(ctx context.Context, c *payment.Confirm) error {
+
+	if err := sp.simulate(ctx); err != nil {
+		return err
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	p, err := sp.find(c.VendorReference)
+	if err != nil {
+		return err
+	}
+
+	p.cancelled = true
+
+	return nil
+}
+
+// Capture takes money held by an authorised payment, returning a Failure if it was not authorised or is asked for too much
+func (sp *SimulatorProcessor) Capture(ctx context.Context, c *payment.Capture) (*payment.Captured, error) {
+
+	if err := sp.simulate(ctx); err != nil {
+		return nil, err
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	p, err := sp.find(c.VendorReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.outcome.Status != payment.Outcome_AUTHORISED || p.cancelled || p.captured > 0 {
+		return nil, &payment.Failure{Code: "capture_failed", Message: "the payment is not authorised"}
+	}
+	if c.Amount > p.amount {
+		return nil, &payment.Failure{Code: "amount_too_large", Message: fmt.Sprintf("only %d can be captured", p.amount)}
+	}
+
+	p.captured = c.Amount
+
+	return &payment.Captured{VendorReference: p.outcome.VendorReference, Amount: c.Amount, Currency: p.currency, Processor: processorNameSimulator}, nil
+}
+
+// Void releases the money held by an authorised payment, returning a Failure if it was not authorised
+func (sp *SimulatorProcessor) Void(ctx context.Context, v *payment.Void) (*payment.Voided, error) {
+
+	if err := sp.simulate(ctx); err != nil {
+		return nil, err
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	p, err := sp.find(v.VendorReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.outcome.Status != payment.Outcome_AUTHORISED || p.captured > 0 {
+		return nil, &payment.Failure{Code: "void_failed", Message: "the payment is not authorised"}
+	}
+
+	p.cancelled = true
+
+	return &payment.Voided{VendorReference: p.outcome.VendorReference, Processor: processorNameSimulator}, nil
+}
+
+// Refund gives back money taken by a payment, returning a Failure if more is asked for than is left
+func (sp *SimulatorProcessor) Refund(ctx context.Context, r *payment.Refund) (*payment.RefundSucceeded, error) {
+
+	if err := sp.simulate(ctx); err != nil {
+		return nil, err
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	p, err := sp.find(r.VendorReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Amount > p.captured-p.refunded {
+		return nil, &payment.Failure{Code: "amount_too_large", Message: fmt.Sprintf("only %d can be refunded", p.captured-p.refunded)}
+	}
+
+	p.refunded += r.Amount
+
+	return &payment.RefundSucceeded{VendorReference: "sim_re_" + r.ID, Amount: r.Amount, Currency: p.currency, Processor: processorNameSimulator}, nil
+}
+
+// find is the payment with the reference, returning a Failure if the simulator has not taken it. It is called with the lock held
+func (sp *SimulatorProcessor) find(reference string) (*simulatorPayment, error) {
+
+	p, ok := sp.payments[reference]
+	if !ok {
+		return nil, &payment.Failure{Code: "resource_missing", Message: fmt.Sprintf("the simulator has no payment %s", reference)}
+	}
+
+	return p, nil
+}
+
+// simulate waits for the latency of the processor, then fails at the failure rate, returning an error, if any
+func (sp *SimulatorProcessor) simulate(ctx context.Context) error {
+
+	if err := sp.wait(ctx, sp.opts.Latency); err != nil {
+		return err
+	}
+
+	if sp.chance(sp.opts.FailureRate) {
+		return payment.Retryable(fmt.Errorf("simulator failed at random"))
+	}
+
+	return nil
+}
+
+// wait waits for the duration, returning a RetryableError if the context is done first
+func (sp *SimulatorProcessor) wait(ctx context.Context, d time.Duration) error {
+
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return payment.Retryable(fmt.Errorf("simulator did not respond in time, %s", ctx.Err().Error()))
+	case <-timer.C:
+		return nil
+	}
+}
+
+// chance reports true at the rate, from the seeded source of the simulator
+func (sp *SimulatorProcessor) chance(rate float64) bool {
+
+	if rate <= 0 {
+		return false
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	return sp.random.Float64() < rate
+}
+
+// clone is a copy of an Outcome, so the one the simulator remembers is not changed by whoever it is returned to
+func clone(o *payment.Outcome) *payment.Outcome {
+	return proto.Clone(o).(*payment.Outcome)
+}
+
+// last4 is the last four digits of a card number
+func last4(number string) string {
+	if len(number) < 4 {
+		return number
+	}
+	return number[len(number)-4:]
+}
+
+// NewSimulatorProcessor is a factory for a SimulatorProcessor which has taken no payments
+func NewSimulatorProcessor(opts SimulatorOptions) *SimulatorProcessor {
+	return &SimulatorProcessor{
+		opts:     opts,
+		random:   rand.New(rand.NewSource(opts.Seed)),
+		payments: map[string]*simulatorPayment{},
+	}
+}
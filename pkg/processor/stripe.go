@@ -1,25 +1,59 @@
 package processor
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/mannion007/payments-prototype/pkg/payment"
 
 	stripe "github.com/stripe/stripe-go"
-	"github.com/stripe/stripe-go/charge"
-	"github.com/stripe/stripe-go/token"
+	"github.com/stripe/stripe-go/client"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-type takePaymentResponse struct {
-	Reference string `json:"reference"`
-	Success   bool   `json:"success"`
+const processorNameStripe = "stripe"
+
+// StripeProcessor is a Processor which talks to stripe over http, using its own client so that many can be used at once
+type StripeProcessor struct {
+	client *client.API
 }
 
-// StripeProcessor is a Processor which talks to wiremock over http
-type StripeProcessor struct{}
+// StripeOptions configures the client a StripeProcessor talks to stripe with
+type StripeOptions struct {
+	APIKey string
+	// BaseURL is where stripe is, which is its live api when left empty
+	BaseURL string
+	// HTTPClient makes the requests to stripe, one with the Timeout is used when it is nil
+	HTTPClient *http.Client
+	// Timeout is how long a request to stripe can take before it is abandoned
+	Timeout time.Duration
+	// MaxNetworkRetries is how many times the client retries a request which failed before it reached stripe
+	MaxNetworkRetries int
+	// ReturnURL is where the payer is sent back to once they have taken an action, with {id} replaced by the id of the payment
+	ReturnURL string
+}
 
 //Process will talk to stripe over http to process the Claim, returing an error, if any
-func (stripeProc StripeProcessor) Process(c *payment.Claim) (*payment.Outcome, error) {
+func (stripeProc StripeProcessor) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return stripeProc.charge(ctx, c, true)
+}
+
+// Authorize will talk to stripe over http to hold the money for the Claim on the card without taking it, returning an error, if any
+func (stripeProc StripeProcessor) Authorize(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return stripeProc.charge(ctx, c, false)
+}
+
+// charge creates a charge for the Claim, which is only captured straight away when asked to be.
+// The requests made to stripe are abandoned when the context is done
+func (stripeProc StripeProcessor) charge(ctx context.Context, c *payment.Claim, capture bool) (*payment.Outcome, error) {
+
+	currency, err := payment.ParseCurrency(c.Amount.Currency)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create card token
 	tokenParams := &stripe.TokenParams{
@@ -29,11 +63,14 @@ func (stripeProc StripeProcessor) Process(c *payment.Claim) (*payment.Outcome, e
 			ExpYear:  stripe.String(c.Payer.ExpiresAt.Year),
 		},
 	}
+	tokenCtx, tokenSent := trackSend(ctx)
+	tokenParams.Context = tokenCtx
+	tokenParams.SetIdempotencyKey(idempotencyKey(c, "token"))
 
-	t, err := token.New(tokenParams)
+	t, err := stripeProc.client.Tokens.New(tokenParams)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create card token, %s", err.Error())
+		return stripeFailure("failed to create card token", err, tokenSent)
 	}
 
 	d := fmt.Sprintf("deko id: %s payee: %s", c.ID, c.Payee)
@@ -41,38 +78,306 @@ func (stripeProc StripeProcessor) Process(c *payment.Claim) (*payment.Outcome, e
 	// Create charge object
 	chargeParams := &stripe.ChargeParams{
 		Amount:      stripe.Int64(int64(c.Amount.Value)),
-		Currency:    stripe.String(string(stripe.CurrencyGBP)),
+		Currency:    stripe.String(strings.ToLower(currency.Code)),
 		Description: stripe.String(d),
 		Source:      &stripe.SourceParams{Token: &t.ID},
+		Capture:     stripe.Bool(capture),
 	}
-	charge, err := charge.New(chargeParams)
+	chargeCtx, chargeSent := trackSend(ctx)
+	chargeParams.Context = chargeCtx
+	chargeAction := "charge"
+	if !capture {
+		chargeAction = "authorize"
+	}
+	chargeParams.SetIdempotencyKey(idempotencyKey(c, chargeAction))
+
+	charge, err := stripeProc.client.Charges.New(chargeParams)
 
 	if err != nil {
-		panic(err)
+		return stripeFailure("failed to create charge", err, chargeSent)
 	}
 
-	success := false
-	if charge.Status == "succeeded" {
-		success = true
+	return chargeOutcome(charge, t), nil
+}
+
+// chargeOutcome describes the Outcome of a charge created by stripe
+func chargeOutcome(ch *stripe.Charge, t *stripe.Token) *payment.Outcome {
+
+	outcome := payment.Outcome{
+		VendorReference: ch.ID,
+		FailureCode:     ch.FailureCode,
+		FailureMessage:  ch.FailureMessage,
+		Amount:          ch.Amount,
+		Currency:        strings.ToUpper(string(ch.Currency)),
+		Processor:       processorNameStripe,
+		VendorCreatedAt: timestamppb.New(time.Unix(ch.Created, 0)),
 	}
 
-	outcome := payment.Outcome{VendorReference: charge.ID, Success: success}
+	switch {
+	case ch.Status == "succeeded" && !ch.Captured:
+		outcome.Status = payment.Outcome_AUTHORISED
+		outcome.Success = true
+	case ch.Status == "succeeded":
+		outcome.Status = payment.Outcome_SUCCEEDED
+		outcome.Success = true
+	case ch.Status == "failed":
+		outcome.Status = payment.Outcome_DECLINED
+	default:
+		outcome.Status = payment.Outcome_ERROR
+		outcome.FailureMessage = fmt.Sprintf("charge has unexpected status %s", ch.Status)
+	}
+
+	if ch.Captured {
+		outcome.AmountCaptured = ch.Amount - ch.AmountRefunded
+	}
+
+	if ch.Outcome != nil {
+		outcome.Risk = &payment.Outcome_Risk{
+			NetworkStatus: ch.Outcome.NetworkStatus,
+			Level:         ch.Outcome.RiskLevel,
+			Score:         ch.Outcome.RiskScore,
+		}
+	}
+
+	if ch.PaymentMethodDetails != nil && ch.PaymentMethodDetails.Card != nil {
+		card := ch.PaymentMethodDetails.Card
+		outcome.Card = &payment.Outcome_Card{Brand: string(card.Brand), Last4: card.Last4, Funding: string(card.Funding)}
+	} else if t != nil && t.Card != nil {
+		outcome.Card = &payment.Outcome_Card{Brand: string(t.Card.Brand), Last4: t.Card.Last4, Funding: string(t.Card.Funding)}
+	}
+
+	return &outcome
+}
+
+// stripeFailure classifies an error from stripe. Declines and invalid requests will never succeed so are an unsuccessful
+// Outcome, whereas network errors, rate limiting and errors within stripe are returned as a RetryableError, which is
+// an UnsentError too when the request never reached stripe
+func stripeFailure(action string, err error, sent *sendTracker) (*payment.Outcome, error) {
+
+	stripeErr, ok := err.(*stripe.Error)
+	if !ok || newStripeErrorDetail(string(stripeErr.Type), string(stripeErr.Code), stripeErr.Msg, stripeErr.HTTPStatusCode).retryable() {
+		return nil, retry(action, err, sent)
+	}
+
+	outcome := payment.Outcome{
+		VendorReference: stripeErr.ChargeID,
+		Success:         false,
+		Status:          payment.Outcome_ERROR,
+		FailureCode:     string(stripeErr.Code),
+		DeclineCode:     string(stripeErr.DeclineCode),
+		FailureMessage:  stripeErr.Msg,
+		Processor:       processorNameStripe,
+	}
+
+	switch {
+	case stripeErr.Code == stripe.ErrorCodeAuthenticationRequired:
+		outcome.Status = payment.Outcome_REQUIRES_ACTION
+	case stripeErr.Type == stripe.ErrorTypeCard:
+		outcome.Status = payment.Outcome_DECLINED
+	}
+
+	if outcome.FailureCode == "" {
+		outcome.FailureCode = string(stripeErr.Type)
+	}
 
 	return &outcome, nil
 }
 
-// NewStripeProcessor is a facotry for a StripeProcessor with sensible defaults
-func NewStripeProcessor() *StripeProcessor {
+// idempotencyKey derives the key for a call to stripe from the Claim, so that retries of the call are only acted on once
+func idempotencyKey(c *payment.Claim, call string) string {
+	return fmt.Sprintf("%s-%s", c.ID, call)
+}
+
+// Refund will talk to stripe over http to give back money taken by a charge, returning an error, if any
+func (stripeProc StripeProcessor) Refund(ctx context.Context, r *payment.Refund) (*payment.RefundSucceeded, error) {
+
+	refundParams := &stripe.RefundParams{
+		Charge: stripe.String(r.VendorReference),
+		Amount: stripe.Int64(r.Amount),
+	}
+	if r.Reason != "" {
+		refundParams.Reason = stripe.String(r.Reason)
+	}
+	refundParams.Context = ctx
+	refundParams.SetIdempotencyKey(fmt.Sprintf("%s-refund", r.ID))
+
+	re, err := stripeProc.client.Refunds.New(refundParams)
+
+	if err != nil {
+		return nil, stripeError("failed to create refund", err)
+	}
+
+	if re.Status == stripe.RefundStatusFailed || re.Status == stripe.RefundStatusCanceled {
+		return nil, &payment.Failure{Code: string(re.FailureReason), Message: fmt.Sprintf("refund %s", re.Status)}
+	}
+
+	succeeded := &payment.RefundSucceeded{
+		VendorReference: re.ID,
+		Amount:          re.Amount,
+		Currency:        strings.ToUpper(string(re.Currency)),
+		Processor:       processorNameStripe,
+	}
+
+	return succeeded, nil
+}
+
+// Capture will talk to stripe over http to take money held by an uncaptured charge, returning an error, if any.
+// Stripe gives back whatever is left of the charge once part of it is captured
+func (stripeProc StripeProcessor) Capture(ctx context.Context, c *payment.Capture) (*payment.Captured, error) {
+
+	captureParams := &stripe.CaptureParams{}
+	if c.Amount > 0 {
+		captureParams.Amount = stripe.Int64(c.Amount)
+	}
+	captureParams.Context = ctx
+	captureParams.SetIdempotencyKey(fmt.Sprintf("%s-capture", c.ID))
 
-	stripe.Key = "sk_test_123"
+	ch, err := stripeProc.client.Charges.Capture(c.VendorReference, captureParams)
 
-	config := &stripe.BackendConfig{
-		URL: "http://localhost:8080",
+	if err != nil {
+		return nil, stripeError("failed to capture charge", err)
 	}
 
-	be := stripe.GetBackendWithConfig(stripe.APIBackend, config)
+	if !ch.Captured {
+		return nil, &payment.Failure{Code: ch.FailureCode, Message: fmt.Sprintf("charge is %s and was not captured", ch.Status)}
+	}
 
-	stripe.SetBackend(stripe.APIBackend, be)
+	captured := &payment.Captured{
+		VendorReference: ch.ID,
+		Amount:          ch.Amount - ch.AmountRefunded,
+		Currency:        strings.ToUpper(string(ch.Currency)),
+		Processor:       processorNameStripe,
+	}
+
+	return captured, nil
+}
+
+// Void will talk to stripe over http to release the money held by an uncaptured charge, returning an error, if any.
+// Stripe releases an uncaptured charge when it is refunded
+func (stripeProc StripeProcessor) Void(ctx context.Context, v *payment.Void) (*payment.Voided, error) {
+
+	refundParams := &stripe.RefundParams{
+		Charge: stripe.String(v.VendorReference),
+	}
+	refundParams.Context = ctx
+	refundParams.SetIdempotencyKey(fmt.Sprintf("%s-void", v.ID))
+
+	re, err := stripeProc.client.Refunds.New(refundParams)
+
+	if err != nil {
+		return nil, stripeError("failed to void charge", err)
+	}
+
+	if re.Status == stripe.RefundStatusFailed || re.Status == stripe.RefundStatusCanceled {
+		return nil, &payment.Failure{Code: string(re.FailureReason), Message: fmt.Sprintf("void %s", re.Status)}
+	}
+
+	voided := &payment.Voided{
+		VendorReference: re.ID,
+		Processor:       processorNameStripe,
+	}
+
+	return voided, nil
+}
+
+// Confirm cannot resume a charge, as stripe declines charges which need the payer to authenticate, so it results in a Failure
+func (stripeProc StripeProcessor) Confirm(ctx context.Context, c *payment.Confirm) (*payment.Outcome, error) {
+	return nil, &payment.Failure{Code: "authentication_not_supported", Message: "charges cannot be authenticated by the payer"}
+}
+
+// Abandon has nothing to give up on, as a charge never waits for the payer to take action
+func (stripeProc StripeProcessor) Abandon(ctx context.Context, c *payment.Confirm) error {
+	return nil
+}
+
+// stripeError classifies an error from stripe when acting on an existing charge, those which may not happen
+// if the request is made again are a RetryableError and the rest are a Failure
+func stripeError(action string, err error) error {
+
+	stripeErr, ok := err.(*stripe.Error)
+	if !ok {
+		return classifyStripeError(action, err, nil)
+	}
+
+	return classifyStripeError(action, err, newStripeErrorDetail(string(stripeErr.Type), string(stripeErr.Code), stripeErr.Msg, stripeErr.HTTPStatusCode))
+}
+
+// stripeErrorDetail is what either version of the stripe client reports about a request stripe did not carry out
+type stripeErrorDetail struct {
+	Type           string
+	Code           string
+	Message        string
+	HTTPStatusCode int
+}
+
+// newStripeErrorDetail is the detail of an error from either version of the stripe client, given the fields both of them report
+func newStripeErrorDetail(errorType, code, msg string, status int) *stripeErrorDetail {
+	return &stripeErrorDetail{Type: errorType, Code: code, Message: msg, HTTPStatusCode: status}
+}
+
+// retryable reports whether the error may not happen if the request is made again,
+// which is the case for network errors, rate limiting and errors within stripe
+func (sed *stripeErrorDetail) retryable() bool {
+	return sed.Type == string(stripe.ErrorTypeAPIConnection) ||
+		sed.Type == string(stripe.ErrorTypeRateLimit) ||
+		sed.HTTPStatusCode == http.StatusTooManyRequests ||
+		sed.HTTPStatusCode == http.StatusConflict ||
+		sed.HTTPStatusCode >= http.StatusInternalServerError
+}
+
+// classifyStripeError classifies an error from either version of the stripe client when acting on an existing payment, given
+// its detail or nil when it did not come from stripe. Those which may not happen if the request is made again are a
+// RetryableError and the rest are a Failure
+func classifyStripeError(action string, err error, detail *stripeErrorDetail) error {
+
+	if detail == nil || detail.retryable() {
+		return payment.Retryable(fmt.Errorf("%s, %s", action, err.Error()))
+	}
+
+	code := detail.Code
+	if code == "" {
+		code = detail.Type
+	}
+
+	return &payment.Failure{Code: code, Message: detail.Message}
+}
+
+// httpClient is what requests to stripe are made with by either version of the stripe client, returning an error if there is no key
+func (opts StripeOptions) httpClient() (*http.Client, error) {
+
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("a stripe api key is required")
+	}
+
+	if opts.HTTPClient != nil {
+		return opts.HTTPClient, nil
+	}
+
+	return &http.Client{Timeout: opts.Timeout}, nil
+}
+
+// NewStripeProcessor is a facotry for a StripeProcessor with a client configured by the options, returning an error if there is no key
+func NewStripeProcessor(opts StripeOptions) (*StripeProcessor, error) {
+
+	httpClient, err := opts.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// stripe fills in the defaults of the config it is given, so each backend needs its own
+	backend := func(backendType stripe.SupportedBackend) stripe.Backend {
+		return stripe.GetBackendWithConfig(backendType, &stripe.BackendConfig{
+			URL:               opts.BaseURL,
+			HTTPClient:        httpClient,
+			MaxNetworkRetries: opts.MaxNetworkRetries,
+		})
+	}
+
+	backends := &stripe.Backends{
+		API:     backend(stripe.APIBackend),
+		Connect: backend(stripe.ConnectBackend),
+		Uploads: backend(stripe.UploadsBackend),
+	}
 
-	return &StripeProcessor{}
+	return &StripeProcessor{client: client.New(opts.APIKey, backends)}, nil
 }
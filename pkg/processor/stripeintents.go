@@ -0,0 +1,359 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+
+	stripe "github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/client"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// StripeIntentsProcessor is a Processor which talks to stripe over http using PaymentIntents and PaymentMethods,
+// which support strong customer authentication by asking for further action when it is needed
+type StripeIntentsProcessor struct {
+	client    *client.API
+	returnURL string
+}
+
+// cancellationReasons are the reasons for a Void which stripe accepts as the reason a payment intent was cancelled
+var cancellationReasons = map[string]bool{
+	"abandoned":             true,
+	"duplicate":             true,
+	"fraudulent":            true,
+	"requested_by_customer": true,
+}
+
+// Process will talk to stripe over http to take the payment for the Claim, returning an error, if any
+func (sip StripeIntentsProcessor) Process(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return sip.confirm(ctx, c, stripe.PaymentIntentCaptureMethodAutomatic)
+}
+
+// Authorize will talk to stripe over http to hold the money for the Claim on the card without taking it, returning an error, if any
+func (sip StripeIntentsProcessor) Authorize(ctx context.Context, c *payment.Claim) (*payment.Outcome, error) {
+	return sip.confirm(ctx, c, stripe.PaymentIntentCaptureMethodManual)
+}
+
+// confirm creates a payment method for the card of the Claim, then creates and confirms a payment intent to pay with it.
+// The requests made to stripe are abandoned when the context is done
+func (sip StripeIntentsProcessor) confirm(ctx context.Context, c *payment.Claim, captureMethod stripe.PaymentIntentCaptureMethod) (*payment.Outcome, error) {
+
+	currency, err := payment.ParseCurrency(c.Amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	pmParams := &stripe.PaymentMethodParams{
+		Type: stripe.String(string(stripe.PaymentMethodTypeCard)),
+		Card: &stripe.PaymentMethodCardParams{
+			Number:   stripe.String(c.Payer.Number),
+			ExpMonth: stripe.String(c.Payer.ExpiresAt.Month),
+			ExpYear:  stripe.String(c.Payer.ExpiresAt.Year),
+		},
+	}
+	pmCtx, pmSent := trackSend(ctx)
+	pmParams.Context = pmCtx
+	pmParams.SetIdempotencyKey(idempotencyKey(c, "payment-method"))
+
+	pm, err := sip.client.PaymentMethods.New(pmParams)
+
+	if err != nil {
+		return intentFailure("failed to create payment method", err, pmSent)
+	}
+
+	piParams := &stripe.PaymentIntentParams{
+		Amount:             stripe.Int64(c.Amount.Value),
+		Currency:           stripe.String(strings.ToLower(currency.Code)),
+		Description:        stripe.String(fmt.Sprintf("deko id: %s payee: %s", c.ID, c.Payee)),
+		PaymentMethod:      stripe.String(pm.ID),
+		PaymentMethodTypes: stripe.StringSlice([]string{string(stripe.PaymentMethodTypeCard)}),
+		CaptureMethod:      stripe.String(string(captureMethod)),
+		Confirm:            stripe.Bool(true),
+	}
+	if sip.returnURL != "" {
+		piParams.ReturnURL = stripe.String(strings.Replace(sip.returnURL, "{id}", c.ID, -1))
+	}
+	piCtx, piSent := trackSend(ctx)
+	piParams.Context = piCtx
+	piParams.SetIdempotencyKey(idempotencyKey(c, "payment-intent"))
+
+	pi, err := sip.client.PaymentIntents.New(piParams)
+
+	if err != nil {
+		return intentFailure("failed to create payment intent", err, piSent)
+	}
+
+	return intentOutcome(pi, pm), nil
+}
+
+// intentOutcome describes the Outcome of a payment intent confirmed by stripe
+func intentOutcome(pi *stripe.PaymentIntent, pm *stripe.PaymentMethod) *payment.Outcome {
+
+	outcome := payment.Outcome{
+		VendorReference: pi.ID,
+		Amount:          pi.Amount,
+		AmountCaptured:  pi.AmountReceived,
+		Currency:        strings.ToUpper(pi.Currency),
+		Processor:       processorNameStripe,
+		VendorCreatedAt: timestamppb.New(time.Unix(pi.Created, 0)),
+	}
+
+	switch pi.Status {
+	case stripe.PaymentIntentStatusSucceeded:
+		outcome.Status = payment.Outcome_SUCCEEDED
+		outcome.Success = true
+	case stripe.PaymentIntentStatusRequiresCapture:
+		outcome.Status = payment.Outcome_AUTHORISED
+		outcome.Success = true
+	case stripe.PaymentIntentStatusRequiresAction:
+		outcome.Status = payment.Outcome_REQUIRES_ACTION
+		outcome.ClientSecret = pi.ClientSecret
+		if pi.NextAction != nil {
+			outcome.NextAction = string(pi.NextAction.Type)
+			if pi.NextAction.RedirectToURL != nil {
+				outcome.RedirectUrl = pi.NextAction.RedirectToURL.URL
+			}
+		}
+	case stripe.PaymentIntentStatusRequiresPaymentMethod:
+		outcome.Status = payment.Outcome_DECLINED
+	default:
+		outcome.Status = payment.Outcome_ERROR
+		outcome.FailureMessage = fmt.Sprintf("payment intent has unexpected status %s", pi.Status)
+	}
+
+	if e := pi.LastPaymentError; e != nil {
+		outcome.FailureCode = string(e.Code)
+		outcome.DeclineCode = string(e.DeclineCode)
+		outcome.FailureMessage = e.Msg
+	}
+
+	if pi.Charges != nil && len(pi.Charges.Data) > 0 {
+		ch := pi.Charges.Data[len(pi.Charges.Data)-1]
+		if ch.Outcome != nil {
+			outcome.Risk = &payment.Outcome_Risk{
+				NetworkStatus: ch.Outcome.NetworkStatus,
+				Level:         ch.Outcome.RiskLevel,
+				Score:         ch.Outcome.RiskScore,
+			}
+		}
+	}
+
+	if pm != nil && pm.Card != nil {
+		outcome.Card = &payment.Outcome_Card{Brand: string(pm.Card.Brand), Last4: pm.Card.Last4, Funding: string(pm.Card.Funding)}
+	}
+
+	return &outcome
+}
+
+// Confirm will talk to stripe over http to find out whether the payer has taken the action a payment intent required,
+// stripe carries on confirming the payment intent once they have, so the Outcome is of wherever it has got to
+func (sip StripeIntentsProcessor) Confirm(ctx context.Context, c *payment.Confirm) (*payment.Outcome, error) {
+
+	piParams := &stripe.PaymentIntentParams{}
+	piParams.Context = ctx
+
+	pi, err := sip.client.PaymentIntents.Get(c.VendorReference, piParams)
+
+	if err != nil {
+		return intentFailure("failed to get payment intent", err, nil)
+	}
+
+	return intentOutcome(pi, nil), nil
+}
+
+// Abandon will talk to stripe over http to cancel a payment intent which is waiting for the payer to take action, returning an error, if any
+func (sip StripeIntentsProcessor) Abandon(ctx context.Context, c *payment.Confirm) error {
+
+	cancelParams := &stripe.PaymentIntentCancelParams{
+		CancellationReason: stripe.String(string(stripe.PaymentIntentCancellationReasonAbandoned)),
+	}
+	cancelParams.Context = ctx
+	cancelParams.SetIdempotencyKey(fmt.Sprintf("%s-abandon", c.ID))
+
+	_, err := sip.client.PaymentIntents.Cancel(c.VendorReference, cancelParams)
+
+	if err != nil {
+		return intentError("failed to cancel payment intent", err)
+	}
+
+	return nil
+}
+
+// intentFailure classifies an error from stripe. Declines and invalid requests will never succeed so are an unsuccessful
+// Outcome, whereas network errors, rate limiting and errors within stripe are returned as a RetryableError, which is
+// an UnsentError too when the request never reached stripe
+func intentFailure(action string, err error, sent *sendTracker) (*payment.Outcome, error) {
+
+	stripeErr, ok := err.(*stripe.Error)
+	if !ok || newStripeErrorDetail(string(stripeErr.Type), string(stripeErr.Code), stripeErr.Msg, stripeErr.HTTPStatusCode).retryable() {
+		return nil, retry(action, err, sent)
+	}
+
+	outcome := payment.Outcome{
+		Success:        false,
+		Status:         payment.Outcome_ERROR,
+		FailureCode:    string(stripeErr.Code),
+		DeclineCode:    string(stripeErr.DeclineCode),
+		FailureMessage: stripeErr.Msg,
+		Processor:      processorNameStripe,
+	}
+
+	if stripeErr.PaymentIntent != nil {
+		outcome.VendorReference = stripeErr.PaymentIntent.ID
+	}
+
+	switch {
+	case stripeErr.Code == stripe.ErrorCodeAuthenticationRequired:
+		outcome.Status = payment.Outcome_REQUIRES_ACTION
+	case stripeErr.Type == stripe.ErrorTypeCard:
+		outcome.Status = payment.Outcome_DECLINED
+	}
+
+	if outcome.FailureCode == "" {
+		outcome.FailureCode = string(stripeErr.Type)
+	}
+
+	return &outcome, nil
+}
+
+// Capture will talk to stripe over http to take money held by a payment intent, returning an error, if any.
+// Stripe releases whatever is left of the payment intent once part of it is captured
+func (sip StripeIntentsProcessor) Capture(ctx context.Context, c *payment.Capture) (*payment.Captured, error) {
+
+	captureParams := &stripe.PaymentIntentCaptureParams{}
+	if c.Amount > 0 {
+		captureParams.AmountToCapture = stripe.Int64(c.Amount)
+	}
+	captureParams.Context = ctx
+	captureParams.SetIdempotencyKey(fmt.Sprintf("%s-capture", c.ID))
+
+	pi, err := sip.client.PaymentIntents.Capture(c.VendorReference, captureParams)
+
+	if err != nil {
+		return nil, intentError("failed to capture payment intent", err)
+	}
+
+	if pi.Status != stripe.PaymentIntentStatusSucceeded {
+		return nil, &payment.Failure{Code: "capture_failed", Message: fmt.Sprintf("payment intent is %s and was not captured", pi.Status)}
+	}
+
+	captured := &payment.Captured{
+		VendorReference: pi.ID,
+		Amount:          pi.AmountReceived,
+		Currency:        strings.ToUpper(pi.Currency),
+		Processor:       processorNameStripe,
+	}
+
+	return captured, nil
+}
+
+// Void will talk to stripe over http to cancel a payment intent, releasing the money it held, returning an error, if any
+func (sip StripeIntentsProcessor) Void(ctx context.Context, v *payment.Void) (*payment.Voided, error) {
+
+	cancelParams := &stripe.PaymentIntentCancelParams{}
+	if cancellationReasons[v.Reason] {
+		cancelParams.CancellationReason = stripe.String(v.Reason)
+	}
+	cancelParams.Context = ctx
+	cancelParams.SetIdempotencyKey(fmt.Sprintf("%s-void", v.ID))
+
+	pi, err := sip.client.PaymentIntents.Cancel(v.VendorReference, cancelParams)
+
+	if err != nil {
+		return nil, intentError("failed to cancel payment intent", err)
+	}
+
+	if pi.Status != stripe.PaymentIntentStatusCanceled {
+		return nil, &payment.Failure{Code: "void_failed", Message: fmt.Sprintf("payment intent is %s and was not cancelled", pi.Status)}
+	}
+
+	voided := &payment.Voided{
+		VendorReference: pi.ID,
+		Processor:       processorNameStripe,
+	}
+
+	return voided, nil
+}
+
+// Refund will talk to stripe over http to give back money taken by a payment intent, returning an error, if any.
+// Payments taken with a charge before payment intents were used are refunded by their charge
+func (sip StripeIntentsProcessor) Refund(ctx context.Context, r *payment.Refund) (*payment.RefundSucceeded, error) {
+
+	refundParams := &stripe.RefundParams{
+		Amount: stripe.Int64(r.Amount),
+	}
+	if strings.HasPrefix(r.VendorReference, "ch_") {
+		refundParams.Charge = stripe.String(r.VendorReference)
+	} else {
+		refundParams.PaymentIntent = stripe.String(r.VendorReference)
+	}
+	if r.Reason != "" {
+		refundParams.Reason = stripe.String(r.Reason)
+	}
+	refundParams.Context = ctx
+	refundParams.SetIdempotencyKey(fmt.Sprintf("%s-refund", r.ID))
+
+	re, err := sip.client.Refunds.New(refundParams)
+
+	if err != nil {
+		return nil, intentError("failed to create refund", err)
+	}
+
+	if re.Status == stripe.RefundStatusFailed || re.Status == stripe.RefundStatusCanceled {
+		return nil, &payment.Failure{Code: string(re.FailureReason), Message: fmt.Sprintf("refund %s", re.Status)}
+	}
+
+	succeeded := &payment.RefundSucceeded{
+		VendorReference: re.ID,
+		Amount:          re.Amount,
+		Currency:        strings.ToUpper(string(re.Currency)),
+		Processor:       processorNameStripe,
+	}
+
+	return succeeded, nil
+}
+
+// intentError classifies an error from stripe when acting on an existing payment intent, those which may not happen
+// if the request is made again are a RetryableError and the rest are a Failure
+func intentError(action string, err error) error {
+
+	stripeErr, ok := err.(*stripe.Error)
+	if !ok {
+		return classifyStripeError(action, err, nil)
+	}
+
+	return classifyStripeError(action, err, newStripeErrorDetail(string(stripeErr.Type), string(stripeErr.Code), stripeErr.Msg, stripeErr.HTTPStatusCode))
+}
+
+// NewStripeIntentsProcessor is a factory for a StripeIntentsProcessor with a client configured by the options, returning an error if there is no key
+func NewStripeIntentsProcessor(opts StripeOptions) (*StripeIntentsProcessor, error) {
+
+	httpClient, err := opts.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// stripe fills in the defaults of the config it is given, so each backend needs its own
+	backend := func(backendType stripe.SupportedBackend) stripe.Backend {
+		config := &stripe.BackendConfig{
+			HTTPClient:        httpClient,
+			MaxNetworkRetries: stripe.Int64(int64(opts.MaxNetworkRetries)),
+		}
+		if opts.BaseURL != "" {
+			config.URL = stripe.String(opts.BaseURL)
+		}
+		return stripe.GetBackendWithConfig(backendType, config)
+	}
+
+	backends := &stripe.Backends{
+		API:     backend(stripe.APIBackend),
+		Connect: backend(stripe.ConnectBackend),
+		Uploads: backend(stripe.UploadsBackend),
+	}
+
+	return &StripeIntentsProcessor{client: client.New(opts.APIKey, backends), returnURL: opts.ReturnURL}, nil
+}
@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptrace"
+	"sync/atomic"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+)
+
+// sendTracker records whether a request, or any retry of it, got a connection to a processor.
+// Until it has, nothing of the request can have reached the processor
+type sendTracker struct {
+	connected int32
+}
+
+// trackSend is a context for making a request with, which records whether it got a connection in the sendTracker
+func trackSend(ctx context.Context) (context.Context, *sendTracker) {
+
+	st := &sendTracker{}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			atomic.StoreInt32(&st.connected, 1)
+		},
+	}
+
+	return httptrace.WithClientTrace(ctx, trace), st
+}
+
+// retry wraps an error from a request which may succeed if it is made again in a RetryableError,
+// which is an UnsentError too when the sendTracker of the request shows it never got a connection
+func retry(action string, err error, sent *sendTracker) error {
+
+	err = fmt.Errorf("%s, %s", action, err.Error())
+	if sent != nil && atomic.LoadInt32(&sent.connected) == 0 {
+		err = payment.Unsent(err)
+	}
+
+	return payment.Retryable(err)
+}
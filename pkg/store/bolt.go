@@ -0,0 +1,176 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	paymentsBucket  = []byte("payments")
+	createdAtBucket = []byte("payments_by_created_at")
+)
+
+// BoltStore is a Store which persists payments to a file using the embedded database bolt
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// Save creates or updates a payment, keeping the time it was created
+func (bs *BoltStore) Save(p *Payment) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+
+		payments := tx.Bucket(paymentsBucket)
+
+		saved := *p
+		if existing := payments.Get([]byte(p.ID)); existing != nil {
+			var prev Payment
+			if err := json.Unmarshal(existing, &prev); err != nil {
+				return fmt.Errorf("failed to unmarshal payment, %s", err.Error())
+			}
+			saved.CreatedAt = prev.CreatedAt
+		}
+
+		b, err := json.Marshal(saved)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payment, %s", err.Error())
+		}
+
+		if err := payments.Put([]byte(p.ID), b); err != nil {
+			return err
+		}
+
+		return tx.Bucket(createdAtBucket).Put(positionKey(positionOf(&saved)), []byte(p.ID))
+	})
+}
+
+// Get finds a payment by the ID of its Claim, returning ErrNotFound if there is none
+func (bs *BoltStore) Get(id string) (*Payment, error) {
+
+	var p *Payment
+
+	err := bs.db.View(func(tx *bolt.Tx) error {
+
+		b := tx.Bucket(paymentsBucket).Get([]byte(id))
+		if b == nil {
+			return ErrNotFound
+		}
+
+		p = &Payment{}
+		if err := json.Unmarshal(b, p); err != nil {
+			return fmt.Errorf("failed to unmarshal payment, %s", err.Error())
+		}
+
+		return nil
+	})
+
+	return p, err
+}
+
+// List finds a page of the payments matching the Query
+func (bs *BoltStore) List(q Query) (*Page, error) {
+
+	page := &Page{Payments: make([]*Payment, 0)}
+
+	err := bs.db.View(func(tx *bolt.Tx) error {
+
+		payments := tx.Bucket(paymentsBucket)
+		c := tx.Bucket(createdAtBucket).Cursor()
+
+		// walk the index from newest to oldest, starting after the cursor if there is one
+		k, v := c.Last()
+		if q.Cursor != "" {
+			pos, err := decodeCursor(q.Cursor)
+			if err != nil {
+				return err
+			}
+			if k, _ = c.Seek(positionKey(pos)); k == nil {
+				k, v = c.Last()
+			} else {
+				k, v = c.Prev()
+			}
+		}
+
+		for ; k != nil; k, v = c.Prev() {
+
+			p := &Payment{}
+			if err := json.Unmarshal(payments.Get(v), p); err != nil {
+				return fmt.Errorf("failed to unmarshal payment, %s", err.Error())
+			}
+
+			if !q.matches(p) {
+				continue
+			}
+
+			if len(page.Payments) == q.limit() {
+				page.NextCursor = encodeCursor(positionOf(page.Payments[len(page.Payments)-1]))
+				return nil
+			}
+
+			page.Payments = append(page.Payments, p)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Truncate removes every payment, so the Store can be rebuilt
+func (bs *BoltStore) Truncate() error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{paymentsBucket, createdAtBucket} {
+			if err := tx.DeleteBucket(bucket); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close releases the database file
+func (bs *BoltStore) Close() error {
+	return bs.db.Close()
+}
+
+// positionKey orders payments in the index by the time they were created, then by id
+func positionKey(p position) []byte {
+	key := make([]byte, 8, 8+len(p.id))
+	binary.BigEndian.PutUint64(key, uint64(p.createdAt.UnixNano()))
+	return append(key, p.id...)
+}
+
+// NewBoltStore is a factory for a BoltStore persisting to the file at path, which is created if needed
+func NewBoltStore(path string) (*BoltStore, error) {
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database, %s", err.Error())
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{paymentsBucket, createdAtBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bolt buckets, %s", err.Error())
+	}
+
+	return &BoltStore{db: db}, nil
+}
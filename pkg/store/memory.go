@@ -0,0 +1,100 @@
+package store
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a Store which keeps payments in memory, they are lost when the process exits
+type MemoryStore struct {
+	lock     sync.RWMutex
+	payments map[string]Payment
+}
+
+// Save creates or updates a payment, keeping the time it was created
+func (ms *MemoryStore) Save(p *Payment) error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	saved := *p
+	if existing, ok := ms.payments[p.ID]; ok {
+		saved.CreatedAt = existing.CreatedAt
+	}
+
+	ms.payments[p.ID] = saved
+
+	return nil
+}
+
+// Get finds a payment by the ID of its Claim, returning ErrNotFound if there is none
+func (ms *MemoryStore) Get(id string) (*Payment, error) {
+
+	ms.lock.RLock()
+	defer ms.lock.RUnlock()
+
+	p, ok := ms.payments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &p, nil
+}
+
+// List finds a page of the payments matching the Query
+func (ms *MemoryStore) List(q Query) (*Page, error) {
+
+	var after *position
+	if q.Cursor != "" {
+		pos, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &pos
+	}
+
+	ms.lock.RLock()
+	matching := make([]*Payment, 0)
+	for _, p := range ms.payments {
+		p := p
+		if q.matches(&p) && (after == nil || positionOf(&p).before(*after)) {
+			matching = append(matching, &p)
+		}
+	}
+	ms.lock.RUnlock()
+
+	sort.Slice(matching, func(i, j int) bool {
+		return positionOf(matching[j]).before(positionOf(matching[i]))
+	})
+
+	page := &Page{Payments: matching}
+	if len(matching) > q.limit() {
+		page.Payments = matching[:q.limit()]
+		page.NextCursor = encodeCursor(positionOf(page.Payments[len(page.Payments)-1]))
+	}
+
+	return page, nil
+}
+
+// Truncate removes every payment, so the Store can be rebuilt
+func (ms *MemoryStore) Truncate() error {
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	ms.payments = make(map[string]Payment)
+
+	return nil
+}
+
+// Close does nothing, there is nothing to release
+func (ms *MemoryStore) Close() error {
+	return nil
+}
+
+// NewMemoryStore is a factory for an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		payments: make(map[string]Payment),
+	}
+}
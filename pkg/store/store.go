@@ -0,0 +1,393 @@
+package store
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mannion007/payments-prototype/pkg/payment"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultLimit is the number of payments in a page when a Query does not specify one
+	DefaultLimit = 20
+	// MaxLimit is the largest number of payments which can be requested in a page
+	MaxLimit = 100
+)
+
+// ErrNotFound is returned when a payment is not in the Store
+var ErrNotFound = errors.New("payment not found")
+
+// Payment is the latest known state of a payment, keyed by the ID of its Claim, along with what its processor reported about it
+type Payment struct {
+	payment.Payment
+	Payee          string    `json:"payee"`
+	FailureCode    string    `json:"failure_code,omitempty"`
+	DeclineCode    string    `json:"decline_code,omitempty"`
+	FailureMessage string    `json:"failure_message,omitempty"`
+	Card           *Card     `json:"card,omitempty"`
+	Risk           *Risk     `json:"risk,omitempty"`
+	NextAction     *Action   `json:"next_action,omitempty"`
+	Attempts       []Attempt `json:"attempts,omitempty"`
+	Refunds        []*Refund `json:"refunds,omitempty"`
+	VoidReason     string    `json:"void_reason,omitempty"`
+	FailedVoids    []string  `json:"failed_voids,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	AuthorisedAt   time.Time `json:"authorised_at"`
+}
+
+// Card describes the card a payment was taken from
+type Card struct {
+	Brand   string `json:"brand"`
+	Last4   string `json:"last4"`
+	Funding string `json:"funding"`
+}
+
+// Risk describes how the card network and processor assessed a payment
+type Risk struct {
+	NetworkStatus string `json:"network_status"`
+	Level         string `json:"level"`
+	Score         int64  `json:"score"`
+}
+
+// Action is what the payer needs to do before a payment which requires action can be processed, either by being
+// redirected to the url, or by a client using the secret
+type Action struct {
+	Type         string `json:"type"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Attempt is a processor the payment was given to, along with the error it failed with if it did not process it
+type Attempt struct {
+	Processor   string    `json:"processor"`
+	Error       string    `json:"error,omitempty"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// Refund is an attempt to give back money taken by a payment
+type Refund struct {
+	ID              string    `json:"id"`
+	Status          string    `json:"status"`
+	Amount          int64     `json:"amount"`
+	VendorReference string    `json:"vendor_reference,omitempty"`
+	FailureCode     string    `json:"failure_code,omitempty"`
+	FailureMessage  string    `json:"failure_message,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// NewPayment is the Payment for a Claim which has been received but not yet processed
+func NewPayment(c *payment.Claim, receivedAt time.Time) *Payment {
+	return &Payment{
+		Payment:   *payment.NewPayment(c),
+		Payee:     c.Payee,
+		CreatedAt: receivedAt,
+		UpdatedAt: receivedAt,
+	}
+}
+
+// ApplyOutcome records the Outcome of processing the Claim of the payment, reporting false if its state does not allow it
+func (p *Payment) ApplyOutcome(o *payment.Outcome) bool {
+
+	if p.Payment.ApplyOutcome(o) != nil {
+		return false
+	}
+
+	p.describe(o)
+	p.UpdatedAt = o.ProcessedAt.AsTime()
+
+	return true
+}
+
+// describe records what the processor reported about the payment in an Outcome
+func (p *Payment) describe(o *payment.Outcome) {
+
+	if o.Payee != "" {
+		p.Payee = o.Payee
+	}
+	p.FailureCode = o.FailureCode
+	p.DeclineCode = o.DeclineCode
+	p.FailureMessage = o.FailureMessage
+
+	if o.Card != nil {
+		p.Card = &Card{Brand: o.Card.Brand, Last4: o.Card.Last4, Funding: o.Card.Funding}
+	}
+
+	if o.Risk != nil {
+		p.Risk = &Risk{NetworkStatus: o.Risk.NetworkStatus, Level: o.Risk.Level, Score: o.Risk.Score}
+	}
+
+	if o.Status == payment.Outcome_AUTHORISED {
+		p.AuthorisedAt = o.ProcessedAt.AsTime()
+	}
+
+	// only processing the claim is failed over, so the attempts are kept when the payment is confirmed
+	if len(o.Attempts) > 0 {
+		p.Attempts = make([]Attempt, 0, len(o.Attempts))
+		for _, a := range o.Attempts {
+			p.Attempts = append(p.Attempts, Attempt{Processor: a.Processor, Error: a.Error, AttemptedAt: a.AttemptedAt.AsTime()})
+		}
+	}
+
+	// the action is only needed until the payer has taken it, and is kept when an outcome which still requires it does not describe it
+	if o.Status != payment.Outcome_REQUIRES_ACTION {
+		p.NextAction = nil
+	} else if o.NextAction != "" || p.NextAction == nil {
+		p.NextAction = &Action{Type: o.NextAction, RedirectURL: o.RedirectUrl, ClientSecret: o.ClientSecret}
+	}
+}
+
+// ApplyAttempts records the processors a pending payment was given to without it being processed, and the one its Claim
+// last reached, if any, so that they are kept when processing it is retried
+func (p *Payment) ApplyAttempts(attempts []*payment.Outcome_Attempt, reached string) {
+
+	for _, a := range attempts {
+		p.Attempts = append(p.Attempts, Attempt{Processor: a.Processor, Error: a.Error, AttemptedAt: a.AttemptedAt.AsTime()})
+	}
+
+	if reached != "" {
+		p.Processor = reached
+	}
+}
+
+// OutcomeAttempts are the processors the payment was given to, as they are recorded in the Attempts of an Outcome
+func (p *Payment) OutcomeAttempts() []*payment.Outcome_Attempt {
+
+	var attempts []*payment.Outcome_Attempt
+	for _, a := range p.Attempts {
+		attempts = append(attempts, &payment.Outcome_Attempt{Processor: a.Processor, Error: a.Error, AttemptedAt: timestamppb.New(a.AttemptedAt)})
+	}
+
+	return attempts
+}
+
+// ApplyRefund records a Refund against the payment, reporting false if it had already been recorded or its state does not allow it
+func (p *Payment) ApplyRefund(r *Refund) bool {
+
+	for _, existing := range p.Refunds {
+		if existing.ID == r.ID {
+			return false
+		}
+	}
+
+	if r.Status == payment.RefundStatusSucceeded && p.Payment.ApplyRefunded(r.Amount) != nil {
+		return false
+	}
+
+	p.Refunds = append(p.Refunds, r)
+	p.UpdatedAt = r.CreatedAt
+
+	return true
+}
+
+// ApplyCapture records the capture of an authorised payment, reporting false if its state does not allow it
+func (p *Payment) ApplyCapture(e *payment.Captured) bool {
+
+	if p.Payment.ApplyCaptured(e.Amount) != nil {
+		return false
+	}
+
+	p.UpdatedAt = e.ProcessedAt.AsTime()
+
+	return true
+}
+
+// ApplyVoid records the release of an authorised payment, reporting false if its state does not allow it
+func (p *Payment) ApplyVoid(e *payment.Voided) bool {
+
+	if p.Payment.ApplyVoided() != nil {
+		return false
+	}
+
+	p.VoidReason = e.Reason
+	p.UpdatedAt = e.ProcessedAt.AsTime()
+
+	return true
+}
+
+// ApplyVoidFailed records a Void of the payment which failed, reporting false if it had already been recorded
+func (p *Payment) ApplyVoidFailed(e *payment.VoidFailed) bool {
+
+	if p.VoidFailed(e.VoidId) {
+		return false
+	}
+
+	p.FailedVoids = append(p.FailedVoids, e.VoidId)
+	p.UpdatedAt = e.ProcessedAt.AsTime()
+
+	return true
+}
+
+// VoidFailed reports whether the Void of the payment with the ID failed
+func (p *Payment) VoidFailed(id string) bool {
+
+	for _, failed := range p.FailedVoids {
+		if failed == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorisedAt is when the payment was authorised, which is taken to be when it was created for those authorised
+// before the time was recorded
+func (p *Payment) authorisedAt() time.Time {
+
+	if p.AuthorisedAt.IsZero() {
+		return p.CreatedAt
+	}
+
+	return p.AuthorisedAt
+}
+
+// RefundFromSucceeded builds the Refund described by a RefundSucceeded event
+func RefundFromSucceeded(e *payment.RefundSucceeded) *Refund {
+	return &Refund{
+		ID:              e.RefundId,
+		Status:          payment.RefundStatusSucceeded,
+		Amount:          e.Amount,
+		VendorReference: e.VendorReference,
+		CreatedAt:       e.ProcessedAt.AsTime(),
+	}
+}
+
+// RefundFromFailed builds the Refund described by a RefundFailed event
+func RefundFromFailed(e *payment.RefundFailed) *Refund {
+	return &Refund{
+		ID:             e.RefundId,
+		Status:         payment.RefundStatusFailed,
+		Amount:         e.Amount,
+		FailureCode:    e.FailureCode,
+		FailureMessage: e.FailureMessage,
+		CreatedAt:      e.ProcessedAt.AsTime(),
+	}
+}
+
+// Query filters the payments listed from a Store, all fields are optional. From and To bound when the payments were created
+type Query struct {
+	Payee  string
+	Status string
+	From   time.Time
+	To     time.Time
+	// AuthorisedBefore only matches payments authorised before it
+	AuthorisedBefore time.Time
+	Cursor           string
+	Limit            int
+}
+
+// Page is a page of payments, newest first, with a cursor for the next page when there are more
+type Page struct {
+	Payments   []*Payment
+	NextCursor string
+}
+
+// Store defines the behaviour required to persist payments
+type Store interface {
+	Save(*Payment) error
+	Get(id string) (*Payment, error)
+	List(Query) (*Page, error)
+	Truncate() error
+	Close() error
+}
+
+// PaymentFromOutcome builds the Payment described by an Outcome, for a Claim which was not recorded when it was received
+func PaymentFromOutcome(o *payment.Outcome) *Payment {
+
+	processedAt := o.ProcessedAt.AsTime()
+
+	p := &Payment{
+		Payment: payment.Payment{
+			ID:              o.ClaimId,
+			Status:          payment.OutcomeStatus(o),
+			VendorReference: o.VendorReference,
+			Amount:          o.Amount,
+			Currency:        o.Currency,
+			AmountCaptured:  o.AmountCaptured,
+			Processor:       o.Processor,
+		},
+		CreatedAt: processedAt,
+		UpdatedAt: processedAt,
+	}
+
+	p.describe(o)
+
+	return p
+}
+
+func (q Query) limit() int {
+	if q.Limit <= 0 {
+		return DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		return MaxLimit
+	}
+	return q.Limit
+}
+
+func (q Query) matches(p *Payment) bool {
+	if q.Payee != "" && q.Payee != p.Payee {
+		return false
+	}
+	if q.Status != "" && q.Status != p.Status {
+		return false
+	}
+	if !q.From.IsZero() && p.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !p.CreatedAt.Before(q.To) {
+		return false
+	}
+	if !q.AuthorisedBefore.IsZero() && !p.authorisedAt().Before(q.AuthorisedBefore) {
+		return false
+	}
+	return true
+}
+
+// position is where a payment sits in the newest first ordering of a Store
+type position struct {
+	createdAt time.Time
+	id        string
+}
+
+// before reports whether a position is listed after (is older than) another
+func (p position) before(other position) bool {
+	if p.createdAt.Equal(other.createdAt) {
+		return p.id < other.id
+	}
+	return p.createdAt.Before(other.createdAt)
+}
+
+func positionOf(p *Payment) position {
+	return position{createdAt: p.CreatedAt, id: p.ID}
+}
+
+func encodeCursor(p position) string {
+	raw := fmt.Sprintf("%d:%s", p.createdAt.UnixNano(), p.id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (position, error) {
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return position{}, fmt.Errorf("invalid cursor, %s", err.Error())
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return position{}, fmt.Errorf("invalid cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return position{}, fmt.Errorf("invalid cursor, %s", err.Error())
+	}
+
+	return position{createdAt: time.Unix(0, nanos).UTC(), id: parts[1]}, nil
+}
@@ -0,0 +1,64 @@
+package transport
+
+import (
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-amqp/pkg/amqp"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// AMQPTransport carries messages through a broker such as rabbit. Commands are sent to a durable queue for each topic,
+// and events to a fanout exchange, which copies them to a durable queue for each named subscriber
+type AMQPTransport struct {
+	uri    string
+	logger watermill.LoggerAdapter
+}
+
+// CommandPublisher publishes commands to the queue of their topic
+func (at AMQPTransport) CommandPublisher() (message.Publisher, error) {
+	return amqp.NewPublisher(amqp.NewDurableQueueConfig(at.uri), at.logger)
+}
+
+// EventPublisher publishes events to the exchange of their topic
+func (at AMQPTransport) EventPublisher() (message.Publisher, error) {
+	return amqp.NewPublisher(amqp.NewDurablePubSubConfig(at.uri, nil), at.logger)
+}
+
+// CommandSubscriber consumes commands from the queue of their topic
+func (at AMQPTransport) CommandSubscriber() (message.Subscriber, error) {
+	return amqp.NewSubscriber(amqp.NewDurableQueueConfig(at.uri), at.logger)
+}
+
+// EventSubscriber consumes events from a durable queue bound to the exchange of their topic, named after the subscriber
+func (at AMQPTransport) EventSubscriber(name string) (message.Subscriber, error) {
+	return amqp.NewSubscriber(
+		amqp.NewDurablePubSubConfig(at.uri, amqp.GenerateQueueNameTopicNameWithSuffix(name)),
+		at.logger,
+	)
+}
+
+// InstanceSubscriber consumes events from a temporary queue for this instance, which is deleted once it unsubscribes
+func (at AMQPTransport) InstanceSubscriber(name string) (message.Subscriber, error) {
+
+	config := amqp.NewNonDurablePubSubConfig(
+		at.uri,
+		amqp.GenerateQueueNameTopicNameWithSuffix(name+"_"+watermill.NewShortUUID()),
+	)
+	config.Queue.AutoDelete = true
+
+	return amqp.NewSubscriber(config, at.logger)
+}
+
+// DeadLetterSubscriber consumes commands from the queue of the dead letter topic, which is a queue of its own
+func (at AMQPTransport) DeadLetterSubscriber() (message.Subscriber, error) {
+	return amqp.NewSubscriber(amqp.NewDurableQueueConfig(at.uri), at.logger)
+}
+
+// Close has nothing to release, as each publisher and subscriber has its own connection to the broker
+func (at AMQPTransport) Close() error {
+	return nil
+}
+
+// NewAMQPTransport is a factory for an AMQPTransport connecting to the broker at the uri
+func NewAMQPTransport(uri string, logger watermill.LoggerAdapter) *AMQPTransport {
+	return &AMQPTransport{uri: uri, logger: logger}
+}
@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+)
+
+// goChannelBuffer is how many messages can wait for each subscriber before publishing blocks
+const goChannelBuffer = 1024
+
+// GoChannelTransport carries messages over go channels within the process, so needs no broker, but loses any which
+// are not handled before the process stops. Every subscriber to a topic receives every message published to it,
+// which for commands is only the one command subscriber
+type GoChannelTransport struct {
+	pubSub *gochannel.GoChannel
+}
+
+// CommandPublisher publishes commands to the subscriber of their topic
+func (gt GoChannelTransport) CommandPublisher() (message.Publisher, error) {
+	return gt.pubSub, nil
+}
+
+// EventPublisher publishes events to every subscriber of their topic
+func (gt GoChannelTransport) EventPublisher() (message.Publisher, error) {
+	return gt.pubSub, nil
+}
+
+// CommandSubscriber receives the commands published to their topic
+func (gt GoChannelTransport) CommandSubscriber() (message.Subscriber, error) {
+	return gt.pubSub, nil
+}
+
+// EventSubscriber receives the events published to their topic, the name is not needed as every subscriber has its own channel
+func (gt GoChannelTransport) EventSubscriber(name string) (message.Subscriber, error) {
+	return gt.pubSub, nil
+}
+
+// InstanceSubscriber receives the events published to their topic, as every EventSubscriber does within the one instance
+func (gt GoChannelTransport) InstanceSubscriber(name string) (message.Subscriber, error) {
+	return gt.pubSub, nil
+}
+
+// DeadLetterSubscriber receives the commands published to the dead letter topic
+func (gt GoChannelTransport) DeadLetterSubscriber() (message.Subscriber, error) {
+	return gt.pubSub, nil
+}
+
+// Close closes the channels of every subscriber
+func (gt GoChannelTransport) Close() error {
+	return gt.pubSub.Close()
+}
+
+// NewGoChannelTransport is a factory for a GoChannelTransport
+func NewGoChannelTransport(logger watermill.LoggerAdapter) *GoChannelTransport {
+	return &GoChannelTransport{
+		pubSub: gochannel.NewGoChannel(gochannel.Config{OutputChannelBuffer: goChannelBuffer}, logger),
+	}
+}
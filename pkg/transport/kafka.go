@@ -0,0 +1,193 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/mannion007/payments-prototype/pkg/payment"
+
+	"github.com/Shopify/sarama"
+)
+
+// KafkaTransport carries messages through kafka. Messages are keyed by the ID of the Claim they are about, so every message
+// for a payment is in the same partition of its topic and is handled in order, while those of different payments are handled
+// in parallel. Commands are shared between the subscribers of one consumer group, and events are copied to a consumer group
+// for each named subscriber
+type KafkaTransport struct {
+	brokers  []string
+	group    string
+	instance string
+	topic    *sarama.TopicDetail
+	logger   watermill.LoggerAdapter
+}
+
+// CommandPublisher publishes commands to the partition of their payment
+func (kt KafkaTransport) CommandPublisher() (message.Publisher, error) {
+	return kt.publisher()
+}
+
+// EventPublisher publishes events to the partition of their payment
+func (kt KafkaTransport) EventPublisher() (message.Publisher, error) {
+	return kt.publisher()
+}
+
+// CommandSubscriber consumes commands in the consumer group of the transport, from the oldest the group has not yet consumed
+func (kt KafkaTransport) CommandSubscriber() (message.Subscriber, error) {
+	return kt.subscriber(kt.group, sarama.OffsetOldest)
+}
+
+// EventSubscriber consumes events in a consumer group for the name, from the oldest the group has not yet consumed
+func (kt KafkaTransport) EventSubscriber(name string) (message.Subscriber, error) {
+	return kt.subscriber(kt.group+"_"+name, sarama.OffsetOldest)
+}
+
+// InstanceSubscriber consumes events in a consumer group named after the instance, from those published once it subscribes.
+// The offsets the group committed before the instance restarted are deleted, so it does not go on from them
+func (kt KafkaTransport) InstanceSubscriber(name string) (message.Subscriber, error) {
+
+	group := kt.group + "_" + name + "_" + kt.instance
+	if err := kt.deleteGroup(group); err != nil {
+		return nil, err
+	}
+
+	return kt.subscriber(group, sarama.OffsetNewest)
+}
+
+// DeadLetterSubscriber consumes the commands which failed every attempt in a consumer group of their own, from the oldest
+// the group has not yet consumed
+func (kt KafkaTransport) DeadLetterSubscriber() (message.Subscriber, error) {
+	return kt.subscriber(kt.group+"_dead_letters", sarama.OffsetOldest)
+}
+
+// Close has nothing to release, as each publisher and subscriber has its own connection to the brokers
+func (kt KafkaTransport) Close() error {
+	return nil
+}
+
+// publisher is a publisher which waits for each message to be written by the brokers
+func (kt KafkaTransport) publisher() (message.Publisher, error) {
+
+	publisher, err := kafka.NewPublisher(
+		kafka.PublisherConfig{
+			Brokers:   kt.brokers,
+			Marshaler: kafka.NewWithPartitioningMarshaler(partitionKey),
+		},
+		kt.logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka publisher, %s", err.Error())
+	}
+
+	return publisher, nil
+}
+
+// subscriber is a subscriber in the consumer group, which starts from the initial offset when the group has consumed nothing
+func (kt KafkaTransport) subscriber(group string, initial int64) (message.Subscriber, error) {
+
+	saramaConfig := kafka.DefaultSaramaSubscriberConfig()
+	saramaConfig.Consumer.Offsets.Initial = initial
+
+	subscriber, err := kafka.NewSubscriber(
+		kafka.SubscriberConfig{
+			Brokers:               kt.brokers,
+			Unmarshaler:           kafka.DefaultMarshaler{},
+			OverwriteSaramaConfig: saramaConfig,
+			ConsumerGroup:         group,
+		},
+		kt.logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka subscriber, %s", err.Error())
+	}
+
+	return &kafkaSubscriber{Subscriber: subscriber, transport: kt}, nil
+}
+
+// createTopic creates the topic with the partitions of the transport, unless it already exists
+func (kt KafkaTransport) createTopic(topic string) error {
+
+	admin, err := sarama.NewClusterAdmin(kt.brokers, kafka.DefaultSaramaSubscriberConfig())
+	if err != nil {
+		return fmt.Errorf("failed to connect to kafka, %s", err.Error())
+	}
+	defer admin.Close()
+
+	err = admin.CreateTopic(topic, kt.topic, false)
+	if topicErr, ok := err.(*sarama.TopicError); ok && topicErr.Err == sarama.ErrTopicAlreadyExists {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create topic %s, %s", topic, err.Error())
+	}
+
+	return nil
+}
+
+// deleteGroup deletes the consumer group and the offsets it committed, unless it does not exist. A group which still has
+// members, such as those of an instance which stopped without leaving it, is left to carry on from its offsets
+func (kt KafkaTransport) deleteGroup(group string) error {
+
+	admin, err := sarama.NewClusterAdmin(kt.brokers, kafka.DefaultSaramaSubscriberConfig())
+	if err != nil {
+		return fmt.Errorf("failed to connect to kafka, %s", err.Error())
+	}
+	defer admin.Close()
+
+	err = admin.DeleteConsumerGroup(group)
+	if err == sarama.ErrGroupIDNotFound || err == sarama.ErrNonEmptyGroup {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete consumer group %s, %s", group, err.Error())
+	}
+
+	return nil
+}
+
+// kafkaSubscriber creates each topic it subscribes to, so that it is partitioned as configured rather than as the brokers default to
+type kafkaSubscriber struct {
+	*kafka.Subscriber
+	transport KafkaTransport
+}
+
+// Subscribe consumes the messages of the topic, creating it if it does not exist, returning an error, if any
+func (ks *kafkaSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+
+	if err := ks.transport.createTopic(topic); err != nil {
+		return nil, err
+	}
+
+	return ks.Subscriber.Subscribe(ctx, topic)
+}
+
+// partitionKey is the ID of the Claim the message is about, so every message about a payment is in the same partition.
+// Messages which are not about a payment, such as changes to the state of circuit breakers, and those which cannot be
+// decoded, such as a dead letter which could not be handled because of it, are spread between partitions
+func partitionKey(topic string, msg *message.Message) (string, error) {
+
+	decoded, err := payment.Unmarshal(msg.Metadata.Get(payment.MessageTypeKey), msg.Payload)
+	if err != nil {
+		return msg.UUID, nil
+	}
+
+	if id := payment.ClaimID(decoded); id != "" {
+		return id, nil
+	}
+
+	return msg.UUID, nil
+}
+
+// NewKafkaTransport is a factory for a KafkaTransport connecting to the brokers, naming consumer groups after the group,
+// and those of this instance alone after its ID too, and creating topics with the partitions, each kept by replicationFactor brokers
+func NewKafkaTransport(brokers []string, group, instance string, partitions int32, replicationFactor int16, logger watermill.LoggerAdapter) *KafkaTransport {
+	return &KafkaTransport{
+		brokers:  brokers,
+		group:    group,
+		instance: instance,
+		topic:    &sarama.TopicDetail{NumPartitions: partitions, ReplicationFactor: replicationFactor},
+		logger:   logger,
+	}
+}
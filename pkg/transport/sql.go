@@ -0,0 +1,267 @@
+package transport
+
+import (
+	"context"
+	stdSQL "database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-sql/pkg/sql"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	// registers the sqlite3 driver with database/sql
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// sqlPollInterval is how often a subscriber which has caught up looks for new messages
+	sqlPollInterval = 100 * time.Millisecond
+	// sqlCommandGroup is the consumer group of every command subscriber, so each command is handled once
+	sqlCommandGroup = "commands"
+	// sqlDeadLetterGroup is the consumer group of every dead letter subscriber, so each dead letter is recorded once
+	sqlDeadLetterGroup = "dead_letters"
+)
+
+// topicName matches the topics which are safe to use as the name of a file and a table
+var topicName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// SQLTransport carries messages through tables of SQLite databases embedded in the process, so needs no broker and keeps
+// the messages which are not yet handled when the process stops. Each topic is kept in a database of its own in the
+// directory, as a subscriber holds the lock on the database of its topic while the message it took is handled, which
+// may publish messages to another topic. Each consumer group receives every message, and each is taken by one subscriber in the group
+type SQLTransport struct {
+	dir      string
+	instance string
+	logger   watermill.LoggerAdapter
+
+	mu  sync.Mutex
+	dbs map[string]*stdSQL.DB
+}
+
+// CommandPublisher appends commands to the table of their topic
+func (st *SQLTransport) CommandPublisher() (message.Publisher, error) {
+	return st.publisher(), nil
+}
+
+// EventPublisher appends events to the table of their topic
+func (st *SQLTransport) EventPublisher() (message.Publisher, error) {
+	return st.publisher(), nil
+}
+
+// CommandSubscriber takes commands from the table of their topic, sharing its place in the table with every other command subscriber
+func (st *SQLTransport) CommandSubscriber() (message.Subscriber, error) {
+	return st.subscriber(sqlCommandGroup, false), nil
+}
+
+// EventSubscriber takes events from the table of their topic, from the place the subscribers with the name last reached
+func (st *SQLTransport) EventSubscriber(name string) (message.Subscriber, error) {
+	return st.subscriber(name, false), nil
+}
+
+// InstanceSubscriber takes events from the table of their topic, from its end when this instance subscribes. Its place
+// in the table is named after the instance, so is moved on again rather than left behind when the instance restarts
+func (st *SQLTransport) InstanceSubscriber(name string) (message.Subscriber, error) {
+	return st.subscriber(name+"_"+st.instance, true), nil
+}
+
+// DeadLetterSubscriber takes commands from the table of the dead letter topic, sharing its place in the table with every other dead letter subscriber
+func (st *SQLTransport) DeadLetterSubscriber() (message.Subscriber, error) {
+	return st.subscriber(sqlDeadLetterGroup, false), nil
+}
+
+// Close closes the database of every topic, returning an error, if any
+func (st *SQLTransport) Close() error {
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for topic, db := range st.dbs {
+		if err := db.Close(); err != nil {
+			return fmt.Errorf("failed to close the database of topic %s, %s", topic, err.Error())
+		}
+		delete(st.dbs, topic)
+	}
+
+	return nil
+}
+
+// db is the database of the topic, which is opened, and created if need be, the first time it is used
+func (st *SQLTransport) db(topic string) (*stdSQL.DB, error) {
+
+	if !topicName.MatchString(topic) {
+		return nil, fmt.Errorf("topic %q cannot be kept in a database", topic)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if db, ok := st.dbs[topic]; ok {
+		return db, nil
+	}
+
+	// transactions take the write lock when they begin, as they go on to write the offset of the message they read
+	dsn := "file:" + filepath.Join(st.dir, topic+".db") + "?_busy_timeout=60000&_journal_mode=WAL&_txlock=immediate"
+
+	db, err := stdSQL.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the database of topic %s, %s", topic, err.Error())
+	}
+
+	st.dbs[topic] = db
+
+	return db, nil
+}
+
+// publisher is a publisher which appends messages to the table of the topic they are published to
+func (st *SQLTransport) publisher() *sqlPublisher {
+	return &sqlPublisher{transport: st, publishers: make(map[string]*sql.Publisher)}
+}
+
+// subscriber is a subscriber in the consumer group, which starts from the end of each table when skip is set
+func (st *SQLTransport) subscriber(group string, skip bool) *sqlSubscriber {
+	return &sqlSubscriber{transport: st, group: group, skip: skip}
+}
+
+// sqlPublisher publishes each message to the database of its topic
+type sqlPublisher struct {
+	transport *SQLTransport
+
+	mu         sync.Mutex
+	publishers map[string]*sql.Publisher
+}
+
+// Publish appends the messages to the table of the topic, returning an error, if any
+func (sp *sqlPublisher) Publish(topic string, messages ...*message.Message) error {
+
+	publisher, err := sp.topic(topic)
+	if err != nil {
+		return err
+	}
+
+	return publisher.Publish(topic, messages...)
+}
+
+// Close closes the publisher of every topic, returning an error, if any
+func (sp *sqlPublisher) Close() error {
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	for topic, publisher := range sp.publishers {
+		if err := publisher.Close(); err != nil {
+			return fmt.Errorf("failed to close the publisher of topic %s, %s", topic, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// topic is the publisher to the database of the topic, which creates its table the first time it is used
+func (sp *sqlPublisher) topic(topic string) (*sql.Publisher, error) {
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if publisher, ok := sp.publishers[topic]; ok {
+		return publisher, nil
+	}
+
+	db, err := sp.transport.db(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	publisher, err := sql.NewPublisher(
+		db,
+		sql.PublisherConfig{SchemaAdapter: sqliteSchema{}, AutoInitializeSchema: true},
+		sp.transport.logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the publisher of topic %s, %s", topic, err.Error())
+	}
+
+	sp.publishers[topic] = publisher
+
+	return publisher, nil
+}
+
+// sqlSubscriber subscribes to the database of each topic as the consumer group
+type sqlSubscriber struct {
+	transport *SQLTransport
+	group     string
+	skip      bool
+
+	mu          sync.Mutex
+	subscribers []*sql.Subscriber
+}
+
+// Subscribe takes the messages of the topic the consumer group has not yet acked, returning an error, if any
+func (ss *sqlSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+
+	db, err := ss.transport.db(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber, err := sql.NewSubscriber(
+		db,
+		sql.SubscriberConfig{
+			ConsumerGroup:    ss.group,
+			PollInterval:     sqlPollInterval,
+			SchemaAdapter:    sqliteSchema{},
+			OffsetsAdapter:   sqliteOffsets{},
+			InitializeSchema: true,
+		},
+		ss.transport.logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the subscriber of topic %s, %s", topic, err.Error())
+	}
+
+	ss.mu.Lock()
+	ss.subscribers = append(ss.subscribers, subscriber)
+	ss.mu.Unlock()
+
+	if ss.skip {
+		if err := subscriber.SubscribeInitialize(topic); err != nil {
+			return nil, fmt.Errorf("failed to create the tables of topic %s, %s", topic, err.Error())
+		}
+		query, args := sqliteOffsets{}.skipQuery(topic, ss.group)
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return nil, fmt.Errorf("failed to skip the messages already in topic %s, %s", topic, err.Error())
+		}
+	}
+
+	return subscriber.Subscribe(ctx, topic)
+}
+
+// Close closes the subscriber of every topic, returning an error, if any
+func (ss *sqlSubscriber) Close() error {
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for _, subscriber := range ss.subscribers {
+		if err := subscriber.Close(); err != nil {
+			return fmt.Errorf("failed to close subscriber, %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// NewSQLTransport is a factory for a SQLTransport keeping the database of each topic in the directory, and the place of
+// the subscribers of this instance alone under its ID, returning an error if the directory cannot be created
+func NewSQLTransport(dir, instance string, logger watermill.LoggerAdapter) (*SQLTransport, error) {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s, %s", dir, err.Error())
+	}
+
+	return &SQLTransport{dir: dir, instance: instance, logger: logger, dbs: make(map[string]*stdSQL.DB)}, nil
+}
@@ -0,0 +1,131 @@
+package transport
+
+import (
+	stdSQL "database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ThreeDotsLabs/watermill-sql/pkg/sql"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// sqliteSchema keeps the messages of a topic in a table of a SQLite database, in the layout of the MySQL schema of watermill
+type sqliteSchema struct{}
+
+// SchemaInitializingQueries creates the table of messages of the topic, if it does not exist
+func (s sqliteSchema) SchemaInitializingQueries(topic string) []string {
+	return []string{`CREATE TABLE IF NOT EXISTS ` + s.messagesTable(topic) + ` (
+		"offset" INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		payload BLOB,
+		metadata TEXT
+	)`}
+}
+
+// InsertQuery appends the messages to the table of the topic, returning an error if their metadata cannot be marshalled
+func (s sqliteSchema) InsertQuery(topic string, msgs message.Messages) (string, []interface{}, error) {
+
+	args := make([]interface{}, 0, len(msgs)*3)
+	for _, msg := range msgs {
+		metadata, err := json.Marshal(msg.Metadata)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal metadata of message %s, %s", msg.UUID, err.Error())
+		}
+		args = append(args, msg.UUID, []byte(msg.Payload), string(metadata))
+	}
+
+	query := `INSERT INTO ` + s.messagesTable(topic) + ` (uuid, payload, metadata) VALUES ` +
+		strings.TrimRight(strings.Repeat(`(?,?,?),`, len(msgs)), ",")
+
+	return query, args, nil
+}
+
+// SelectQuery selects the first message of the topic after the last one the consumer group acked
+func (s sqliteSchema) SelectQuery(topic string, consumerGroup string, offsetsAdapter sql.OffsetsAdapter) (string, []interface{}) {
+
+	nextOffsetQuery, nextOffsetArgs := offsetsAdapter.NextOffsetQuery(topic, consumerGroup)
+
+	query := `SELECT "offset", uuid, payload, metadata FROM ` + s.messagesTable(topic) +
+		` WHERE "offset" > (` + nextOffsetQuery + `) ORDER BY "offset" ASC LIMIT 1`
+
+	return query, nextOffsetArgs
+}
+
+// UnmarshalMessage reads the message, and its offset, from the row returned by the SelectQuery, returning an error if it cannot
+func (s sqliteSchema) UnmarshalMessage(row *stdSQL.Row) (int, *message.Message, error) {
+
+	var offset int
+	var uuid string
+	var payload []byte
+	var metadata stdSQL.NullString
+
+	if err := row.Scan(&offset, &uuid, &payload, &metadata); err != nil {
+		// the subscriber finds there are no more messages by the cause of the error, so it is not wrapped
+		if err == stdSQL.ErrNoRows {
+			return 0, nil, err
+		}
+		return 0, nil, fmt.Errorf("failed to scan message row, %s", err.Error())
+	}
+
+	msg := message.NewMessage(uuid, payload)
+	if metadata.Valid {
+		if err := json.Unmarshal([]byte(metadata.String), &msg.Metadata); err != nil {
+			return 0, nil, fmt.Errorf("failed to unmarshal metadata of message %s, %s", uuid, err.Error())
+		}
+	}
+
+	return offset, msg, nil
+}
+
+// messagesTable is the name of the table of messages of the topic
+func (s sqliteSchema) messagesTable(topic string) string {
+	return `"watermill_` + topic + `"`
+}
+
+// sqliteOffsets keeps the offset of the last message of a topic acked by each consumer group in a table of a SQLite database.
+// SQLite has no row locks, so consumers are kept from taking the same message by beginning each transaction with the write lock
+type sqliteOffsets struct{}
+
+// SchemaInitializingQueries creates the table of offsets of the topic, if it does not exist
+func (o sqliteOffsets) SchemaInitializingQueries(topic string) []string {
+	return []string{`CREATE TABLE IF NOT EXISTS ` + o.offsetsTable(topic) + ` (
+		consumer_group TEXT NOT NULL PRIMARY KEY,
+		offset_acked INTEGER,
+		offset_consumed INTEGER NOT NULL
+	)`}
+}
+
+// AckMessageQuery records the offset as the last acked by the consumer group
+func (o sqliteOffsets) AckMessageQuery(topic string, offset int, consumerGroup string) (string, []interface{}) {
+	return `UPDATE ` + o.offsetsTable(topic) + ` SET offset_acked = ? WHERE consumer_group = ?`,
+		[]interface{}{offset, consumerGroup}
+}
+
+// ConsumedMessageQuery records the offset as the last taken by the consumer group, adding the group when it is new
+func (o sqliteOffsets) ConsumedMessageQuery(topic string, offset int, consumerGroup string, consumerULID []byte) (string, []interface{}) {
+	return `INSERT INTO ` + o.offsetsTable(topic) + ` (offset_consumed, consumer_group) VALUES (?, ?)
+		ON CONFLICT(consumer_group) DO UPDATE SET offset_consumed = excluded.offset_consumed`,
+		[]interface{}{offset, consumerGroup}
+}
+
+// NextOffsetQuery selects the offset of the last message acked by the consumer group, or 0 when it has acked none
+func (o sqliteOffsets) NextOffsetQuery(topic, consumerGroup string) (string, []interface{}) {
+	return `SELECT COALESCE((SELECT offset_acked FROM ` + o.offsetsTable(topic) + ` WHERE consumer_group = ?), 0)`,
+		[]interface{}{consumerGroup}
+}
+
+// skipQuery moves the consumer group past every message already in the topic, so it only receives those which follow,
+// including when the group has already consumed some of them
+func (o sqliteOffsets) skipQuery(topic, consumerGroup string) (string, []interface{}) {
+	return `INSERT INTO ` + o.offsetsTable(topic) + ` (consumer_group, offset_acked, offset_consumed)
+		SELECT ?, m.last, m.last FROM (SELECT COALESCE(MAX("offset"), 0) AS last FROM ` + sqliteSchema{}.messagesTable(topic) + `) AS m
+		WHERE true ON CONFLICT(consumer_group) DO UPDATE SET offset_acked = excluded.offset_acked, offset_consumed = excluded.offset_consumed`,
+		[]interface{}{consumerGroup}
+}
+
+// offsetsTable is the name of the table of offsets of the topic
+func (o sqliteOffsets) offsetsTable(topic string) string {
+	return `"watermill_offsets_` + topic + `"`
+}
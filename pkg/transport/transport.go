@@ -0,0 +1,20 @@
+package transport
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// Transport creates the publishers and subscribers which carry commands and events between the handlers of the service.
+// Each command is handled once, by whichever command subscriber receives it, whereas every event subscriber receives every event
+type Transport interface {
+	CommandPublisher() (message.Publisher, error)
+	EventPublisher() (message.Publisher, error)
+	CommandSubscriber() (message.Subscriber, error)
+	// EventSubscriber receives every event under its name, including those published while it was not subscribed when the transport keeps them
+	EventSubscriber(name string) (message.Subscriber, error)
+	// InstanceSubscriber receives every event published while this instance of the service is subscribed, and no others
+	InstanceSubscriber(name string) (message.Subscriber, error)
+	// DeadLetterSubscriber receives the commands which failed every attempt, apart from the subscribers of every other command
+	DeadLetterSubscriber() (message.Subscriber, error)
+	Close() error
+}